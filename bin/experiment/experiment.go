@@ -11,7 +11,15 @@ import (
 	// _ "k8s.io/client-go/plugin/pkg/client/auth/oidc"
 	// _ "k8s.io/client-go/plugin/pkg/client/auth/openstack"
 
+	vmwareCDROMDisconnect "github.com/chaosnative/litmus-go/experiments/vmware/vmware-cdrom-disconnect/experiment"
+	vmwareDeviceDisconnect "github.com/chaosnative/litmus-go/experiments/vmware/vmware-device-disconnect/experiment"
+	vmwareDiskLossRevert "github.com/chaosnative/litmus-go/experiments/vmware/vmware-disk-loss-revert/experiment"
 	vmwareDiskLoss "github.com/chaosnative/litmus-go/experiments/vmware/vmware-disk-loss/experiment"
+	vmwareHostMaintenance "github.com/chaosnative/litmus-go/experiments/vmware/vmware-host-maintenance/experiment"
+	vmwareNICDisconnect "github.com/chaosnative/litmus-go/experiments/vmware/vmware-nic-disconnect/experiment"
+	vmwareVMGuestReboot "github.com/chaosnative/litmus-go/experiments/vmware/vmware-vm-guest-reboot/experiment"
+	vmwareVMPowerOff "github.com/chaosnative/litmus-go/experiments/vmware/vmware-vm-poweroff/experiment"
+	vmwareVMSuspend "github.com/chaosnative/litmus-go/experiments/vmware/vmware-vm-suspend/experiment"
 
 	"github.com/litmuschaos/litmus-go/pkg/clients"
 	"github.com/litmuschaos/litmus-go/pkg/log"
@@ -46,6 +54,22 @@ func main() {
 	switch *experimentName {
 	case "vmware-disk-loss":
 		vmwareDiskLoss.VMWareDiskLoss(clients)
+	case "vmware-disk-loss-revert":
+		vmwareDiskLossRevert.VMWareDiskLossRevert(clients)
+	case "vmware-vm-poweroff":
+		vmwareVMPowerOff.VMWareVMPowerOff(clients)
+	case "vmware-vm-guest-reboot":
+		vmwareVMGuestReboot.VMWareVMGuestReboot(clients)
+	case "vmware-vm-suspend":
+		vmwareVMSuspend.VMWareVMSuspend(clients)
+	case "vmware-nic-disconnect":
+		vmwareNICDisconnect.VMWareNICDisconnect(clients)
+	case "vmware-device-disconnect":
+		vmwareDeviceDisconnect.VMWareDeviceDisconnect(clients)
+	case "vmware-cdrom-disconnect":
+		vmwareCDROMDisconnect.VMWareCDROMDisconnect(clients)
+	case "vmware-host-maintenance":
+		vmwareHostMaintenance.VMWareHostMaintenance(clients)
 
 	default:
 		log.Errorf("Unsupported -name %v, please provide the correct value of -name args", *experimentName)
@@ -0,0 +1,191 @@
+package lib
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/chaosnative/litmus-go/pkg/cloud/vmware"
+	"github.com/chaosnative/litmus-go/pkg/vmware/recovery"
+	experimentTypes "github.com/chaosnative/litmus-go/pkg/vmware/vmware-device-chaos/types"
+	"github.com/litmuschaos/litmus-go/pkg/clients"
+	"github.com/litmuschaos/litmus-go/pkg/log"
+	"github.com/pkg/errors"
+)
+
+//diskTarget contains the details of a target disk captured before its detachment
+type diskTarget struct {
+	//info contains the vmdk path and the controller slot of the disk, info.Disk is the current id of the disk
+	info vmware.DiskInfo
+	//recordedId is the id of the disk in the recovery store
+	recordedId string
+}
+
+//diskHandler detaches and reattaches the virtual disks
+//the disk is removed from the vm hardware while it is detached, so it gets a new id once it is reattached
+//the detached disks are persisted in the recovery store, so that they are reattached by a later run if the revert does not complete
+type diskHandler struct {
+	client          *vmware.VcenterClient
+	store           *recovery.Store
+	allowBootDetach bool
+
+	// mu guards the targets, which are updated by the chaos injection and read by the abort revert
+	mu      sync.Mutex
+	targets map[string]*diskTarget
+}
+
+//newDiskHandler returns the disk handler, the detached disks are recorded in the recovery configmap of the experiment
+func newDiskHandler(experimentsDetails *experimentTypes.ExperimentDetails, clients clients.ClientSets, vcenterClient *vmware.VcenterClient) DeviceHandler {
+	return &diskHandler{
+		client:          vcenterClient,
+		store:           recovery.NewStore(clients, experimentsDetails.ChaosNamespace, recovery.StoreName(experimentsDetails.EngineName, experimentsDetails.ExperimentName)),
+		allowBootDetach: experimentsDetails.AllowBootDetach,
+		targets:         map[string]*diskTarget{},
+	}
+}
+
+func (h *diskHandler) Kind() string {
+	return "Disk"
+}
+
+func (h *diskHandler) Resolve(ctx context.Context, appVMMoid, deviceSelector string) (string, error) {
+	return h.client.ResolveDiskSelector(ctx, appVMMoid, deviceSelector)
+}
+
+func (h *diskHandler) PreCheck(ctx context.Context, appVMMoid, deviceId string) error {
+	if h.allowBootDetach {
+		log.Warnf("[Warning]: Boot disk detachment is allowed, %v vm may go down if %v disk is its boot disk", appVMMoid, deviceId)
+		return nil
+	}
+	return h.client.BootDiskCheck(ctx, appVMMoid, deviceId)
+}
+
+func (h *diskHandler) Prepare(ctx context.Context, appVMMoid, deviceId string) error {
+
+	//the vmdk path and the controller slot are captured before detachment, so that the disk is reattached to its original slot
+	diskInfo, err := h.client.GetDiskInfo(ctx, appVMMoid, h.currentId(appVMMoid, deviceId))
+	if err != nil {
+		return err
+	}
+
+	if err = h.store.Record(recovery.DetachedDisk{
+		VMMoid:         appVMMoid,
+		DiskId:         diskInfo.Disk,
+		DiskPath:       diskInfo.VMDKFile,
+		ControllerType: diskInfo.Type,
+		Bus:            diskInfo.Bus,
+		Unit:           diskInfo.Unit,
+		DetachTime:     time.Now(),
+	}); err != nil {
+		return errors.Errorf("failed to record the disk details, err: %v", err)
+	}
+
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.targets[targetKey(appVMMoid, deviceId)] = &diskTarget{info: diskInfo, recordedId: diskInfo.Disk}
+	return nil
+}
+
+//IsAttached looks up the disk using its vmdk file, as the disk id changes once the disk is reattached
+func (h *diskHandler) IsAttached(ctx context.Context, appVMMoid, deviceId string) (bool, error) {
+
+	target := h.target(appVMMoid, deviceId)
+	if target == nil {
+		diskState, err := h.client.GetDiskState(ctx, appVMMoid, deviceId)
+		return diskState == "attached", err
+	}
+
+	attachedDiskId, err := h.client.GetDiskIdByPath(ctx, appVMMoid, target.info.VMDKFile)
+	if err != nil {
+		return false, err
+	}
+
+	if attachedDiskId == "" {
+		return false, nil
+	}
+
+	h.trackDiskId(appVMMoid, deviceId, attachedDiskId)
+	return true, nil
+}
+
+func (h *diskHandler) Detach(ctx context.Context, appVMMoid, deviceId string) error {
+	return h.client.DiskDetach(ctx, appVMMoid, h.currentId(appVMMoid, deviceId))
+}
+
+func (h *diskHandler) WaitForDetach(ctx context.Context, appVMMoid, deviceId string, delay, timeout int) error {
+	return h.client.WaitForDiskDetachment(ctx, appVMMoid, h.currentId(appVMMoid, deviceId), delay, timeout)
+}
+
+func (h *diskHandler) Attach(ctx context.Context, appVMMoid, deviceId string) error {
+
+	target := h.target(appVMMoid, deviceId)
+	if target == nil {
+		return errors.Errorf("no details captured for %v disk before its detachment", deviceId)
+	}
+
+	attachedDiskId, err := h.client.DiskAttachAt(ctx, appVMMoid, target.info)
+	if err != nil {
+		return err
+	}
+
+	h.trackDiskId(appVMMoid, deviceId, attachedDiskId)
+	return nil
+}
+
+func (h *diskHandler) WaitForAttach(ctx context.Context, appVMMoid, deviceId string, delay, timeout int) error {
+	return h.client.WaitForDiskAttachment(ctx, appVMMoid, h.currentId(appVMMoid, deviceId), delay, timeout)
+}
+
+//Release removes the recovery record of the reattached disk
+func (h *diskHandler) Release(ctx context.Context, appVMMoid, deviceId string) error {
+
+	target := h.target(appVMMoid, deviceId)
+	if target == nil {
+		return nil
+	}
+
+	if err := h.store.Remove(appVMMoid, target.recordedId); err != nil {
+		return errors.Errorf("failed to remove the recovery record of %v disk, err: %v", deviceId, err)
+	}
+	return nil
+}
+
+//target returns a copy of the captured details of the disk, or nil if they are not captured yet
+func (h *diskHandler) target(appVMMoid, deviceId string) *diskTarget {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	target, ok := h.targets[targetKey(appVMMoid, deviceId)]
+	if !ok {
+		return nil
+	}
+	targetCopy := *target
+	return &targetCopy
+}
+
+//currentId returns the current id of the target disk, which differs from the target id once the disk is reattached
+func (h *diskHandler) currentId(appVMMoid, deviceId string) string {
+	if target := h.target(appVMMoid, deviceId); target != nil {
+		return target.info.Disk
+	}
+	return deviceId
+}
+
+//trackDiskId updates the current id of the target disk, so that the subsequent iterations and the revert use it
+func (h *diskHandler) trackDiskId(appVMMoid, deviceId, attachedDiskId string) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	target, ok := h.targets[targetKey(appVMMoid, deviceId)]
+	if !ok || attachedDiskId == "" || attachedDiskId == target.info.Disk {
+		return
+	}
+
+	log.Infof("[Info]: %s disk is reattached as %s disk", deviceId, attachedDiskId)
+	target.info.Disk = attachedDiskId
+}
+
+//targetKey returns the key of the target device
+func targetKey(appVMMoid, deviceId string) string {
+	return appVMMoid + "." + deviceId
+}
@@ -0,0 +1,147 @@
+package lib
+
+import (
+	"context"
+	"sort"
+	"strings"
+
+	"github.com/chaosnative/litmus-go/pkg/cloud/vmware"
+	experimentTypes "github.com/chaosnative/litmus-go/pkg/vmware/vmware-device-chaos/types"
+	"github.com/litmuschaos/litmus-go/pkg/clients"
+	"github.com/pkg/errors"
+)
+
+//DeviceHandler detaches and reattaches the virtual devices of a given type
+//the chaos lib is independent of the device type, a new device type only needs a new handler
+type DeviceHandler interface {
+	//Kind returns the target kind of the device in the chaos result, e.g, CDROM
+	Kind() string
+	//Resolve returns the id of the device of the vm matching the given selector
+	Resolve(ctx context.Context, appVMMoid, deviceSelector string) (string, error)
+	//PreCheck verifies that the device can be detached from the vm, it is called for every target device before the chaos injection
+	PreCheck(ctx context.Context, appVMMoid, deviceId string) error
+	//Prepare captures the device details required for the reattachment, it is called before every detachment
+	Prepare(ctx context.Context, appVMMoid, deviceId string) error
+	//IsAttached returns true if the device is attached to the vm
+	IsAttached(ctx context.Context, appVMMoid, deviceId string) (bool, error)
+	//Detach detaches the device from the vm
+	Detach(ctx context.Context, appVMMoid, deviceId string) error
+	//WaitForDetach waits for the device to get detached from the vm
+	WaitForDetach(ctx context.Context, appVMMoid, deviceId string, delay, timeout int) error
+	//Attach reattaches the device to the vm
+	Attach(ctx context.Context, appVMMoid, deviceId string) error
+	//WaitForAttach waits for the device to get attached to the vm
+	WaitForAttach(ctx context.Context, appVMMoid, deviceId string, delay, timeout int) error
+	//Release drops the device details captured by Prepare, once the device is reattached
+	Release(ctx context.Context, appVMMoid, deviceId string) error
+}
+
+//newDeviceHandler is the constructor of the device handler of a device type
+type newDeviceHandler func(experimentsDetails *experimentTypes.ExperimentDetails, clients clients.ClientSets, vcenterClient *vmware.VcenterClient) DeviceHandler
+
+//deviceHandlers contains the constructors of the device handlers for every supported device type
+var deviceHandlers = map[string]newDeviceHandler{
+	vmware.DeviceTypeCdrom:    connectableDeviceHandler(vmware.DeviceTypeCdrom, "CDROM"),
+	vmware.DeviceTypeSerial:   connectableDeviceHandler(vmware.DeviceTypeSerial, "SerialPort"),
+	vmware.DeviceTypeFloppy:   connectableDeviceHandler(vmware.DeviceTypeFloppy, "Floppy"),
+	vmware.DeviceTypeEthernet: newNICHandler,
+	vmware.DeviceTypeDisk:     newDiskHandler,
+}
+
+//RegisterDeviceHandler registers the handler constructor for the given device type
+func RegisterDeviceHandler(deviceType string, newHandler newDeviceHandler) {
+	deviceHandlers[strings.ToLower(deviceType)] = newHandler
+}
+
+//NewDeviceHandler returns the handler of the device type of the experiment
+func NewDeviceHandler(experimentsDetails *experimentTypes.ExperimentDetails, clients clients.ClientSets, vcenterClient *vmware.VcenterClient) (DeviceHandler, error) {
+	newHandler, ok := deviceHandlers[strings.ToLower(experimentsDetails.DeviceType)]
+	if !ok {
+		return nil, errors.Errorf("%v device type is not supported, supported device types are %v", experimentsDetails.DeviceType, SupportedDeviceTypes())
+	}
+	return newHandler(experimentsDetails, clients, vcenterClient), nil
+}
+
+//SupportedDeviceTypes returns the sorted list of the supported device types
+func SupportedDeviceTypes() []string {
+	var deviceTypeList []string
+	for deviceType := range deviceHandlers {
+		deviceTypeList = append(deviceTypeList, deviceType)
+	}
+	sort.Strings(deviceTypeList)
+	return deviceTypeList
+}
+
+//connectableDeviceHandler returns the handler constructor of a device type which is disconnected and connected in place
+func connectableDeviceHandler(deviceType, kind string) newDeviceHandler {
+	return func(experimentsDetails *experimentTypes.ExperimentDetails, clients clients.ClientSets, vcenterClient *vmware.VcenterClient) DeviceHandler {
+		return &connectableHandler{client: vcenterClient, deviceType: deviceType, kind: kind}
+	}
+}
+
+//connectableHandler disconnects and connects the cdrom, serial, floppy and ethernet devices
+//the device stays in the vm hardware while it is disconnected, so its id never changes
+type connectableHandler struct {
+	client     *vmware.VcenterClient
+	deviceType string
+	kind       string
+}
+
+func (h *connectableHandler) Kind() string {
+	return h.kind
+}
+
+func (h *connectableHandler) Resolve(ctx context.Context, appVMMoid, deviceSelector string) (string, error) {
+	return h.client.ResolveDeviceSelector(ctx, appVMMoid, h.deviceType, deviceSelector)
+}
+
+func (h *connectableHandler) PreCheck(ctx context.Context, appVMMoid, deviceId string) error {
+	return nil
+}
+
+func (h *connectableHandler) Prepare(ctx context.Context, appVMMoid, deviceId string) error {
+	return nil
+}
+
+func (h *connectableHandler) IsAttached(ctx context.Context, appVMMoid, deviceId string) (bool, error) {
+	state, err := h.client.GetDeviceState(ctx, appVMMoid, h.deviceType, deviceId)
+	if err != nil {
+		return false, err
+	}
+	return state == vmware.DeviceStateConnected, nil
+}
+
+func (h *connectableHandler) Detach(ctx context.Context, appVMMoid, deviceId string) error {
+	return h.client.DeviceDisconnect(ctx, appVMMoid, h.deviceType, deviceId)
+}
+
+func (h *connectableHandler) WaitForDetach(ctx context.Context, appVMMoid, deviceId string, delay, timeout int) error {
+	return h.client.WaitForDeviceState(ctx, appVMMoid, h.deviceType, deviceId, vmware.DeviceStateNotConnected, delay, timeout)
+}
+
+func (h *connectableHandler) Attach(ctx context.Context, appVMMoid, deviceId string) error {
+	return h.client.DeviceConnect(ctx, appVMMoid, h.deviceType, deviceId)
+}
+
+func (h *connectableHandler) WaitForAttach(ctx context.Context, appVMMoid, deviceId string, delay, timeout int) error {
+	return h.client.WaitForDeviceState(ctx, appVMMoid, h.deviceType, deviceId, vmware.DeviceStateConnected, delay, timeout)
+}
+
+func (h *connectableHandler) Release(ctx context.Context, appVMMoid, deviceId string) error {
+	return nil
+}
+
+//nicHandler disconnects and connects the ethernet devices
+//the nics are additionally selected using their mac address, as the vms often have identical nic labels
+type nicHandler struct {
+	connectableHandler
+}
+
+//newNICHandler returns the handler of the ethernet devices
+func newNICHandler(experimentsDetails *experimentTypes.ExperimentDetails, clients clients.ClientSets, vcenterClient *vmware.VcenterClient) DeviceHandler {
+	return &nicHandler{connectableHandler{client: vcenterClient, deviceType: vmware.DeviceTypeEthernet, kind: "NIC"}}
+}
+
+func (h *nicHandler) Resolve(ctx context.Context, appVMMoid, deviceSelector string) (string, error) {
+	return h.client.ResolveNICSelector(ctx, appVMMoid, deviceSelector)
+}
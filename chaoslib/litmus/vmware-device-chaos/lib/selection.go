@@ -0,0 +1,35 @@
+package lib
+
+import (
+	"math/rand"
+	"sort"
+	"time"
+
+	"github.com/litmuschaos/litmus-go/pkg/log"
+	"github.com/litmuschaos/litmus-go/pkg/math"
+)
+
+//NewRandomGenerator returns the random generator used to select the target devices
+//the same RANDOM_SEED selects the same target devices in every run
+func NewRandomGenerator(affectedPerc int, randomSeed int64) *rand.Rand {
+	if randomSeed == 0 {
+		randomSeed = time.Now().UnixNano()
+	}
+	log.Infof("[Info]: Selecting %v%% of the devices in every iteration, random seed: %v", affectedPerc, randomSeed)
+	return rand.New(rand.NewSource(randomSeed))
+}
+
+//PickTargets returns the sorted random indices of the target devices out of the given number of devices
+func PickTargets(rng *rand.Rand, deviceCount, affectedPerc int) []int {
+	targetIndexList := rng.Perm(deviceCount)[:TargetCount(deviceCount, affectedPerc)]
+	sort.Ints(targetIndexList)
+	return targetIndexList
+}
+
+//TargetCount returns the number of target devices in an iteration, based on the device affected percentage
+func TargetCount(deviceCount, affectedPerc int) int {
+	if affectedPerc >= 100 {
+		return deviceCount
+	}
+	return math.Maximum(1, math.Adjustment(affectedPerc, deviceCount))
+}
@@ -0,0 +1,86 @@
+package lib
+
+import (
+	"context"
+	"strings"
+
+	"github.com/chaosnative/litmus-go/pkg/cloud/vmware"
+	experimentTypes "github.com/chaosnative/litmus-go/pkg/vmware/vmware-device-chaos/types"
+	"github.com/pkg/errors"
+)
+
+//ResolveTargets derives the target vm moids and device ids from the provided vm names and device selectors
+func ResolveTargets(ctx context.Context, experimentsDetails *experimentTypes.ExperimentDetails, handler DeviceHandler, vcenterClient *vmware.VcenterClient) error {
+	var err error
+
+	//resolve the target vm names into the vm moids
+	if experimentsDetails.AppVMNames != "" {
+		if experimentsDetails.AppVMMoids != "" {
+			return errors.Errorf("both APP_VM_MOIDS and APP_VM_NAMES are provided, please provide only one of them")
+		}
+
+		if experimentsDetails.AppVMMoids, err = vcenterClient.GetVMMoids(ctx, experimentsDetails.AppVMNames); err != nil {
+			return errors.Errorf("failed to resolve the vm names, err: %v", err)
+		}
+	}
+
+	//resolve the device selectors into the device ids
+	if experimentsDetails.DeviceSelectors != "" {
+		if experimentsDetails.DeviceIds != "" {
+			return errors.Errorf("both VIRTUAL_DEVICE_IDS and VIRTUAL_DEVICE_SELECTORS are provided, please provide only one of them")
+		}
+
+		appVMMoidList := strings.Split(experimentsDetails.AppVMMoids, ",")
+		deviceSelectorList := strings.Split(experimentsDetails.DeviceSelectors, ",")
+		if len(appVMMoidList) != len(deviceSelectorList) {
+			return errors.Errorf("unequal number of device selectors and vm ids found, please verify the input details")
+		}
+
+		var deviceIdList []string
+		for i := range deviceSelectorList {
+
+			deviceId, err := handler.Resolve(ctx, strings.TrimSpace(appVMMoidList[i]), strings.TrimSpace(deviceSelectorList[i]))
+			if err != nil {
+				return errors.Errorf("failed to resolve the device selectors, err: %v", err)
+			}
+
+			deviceIdList = append(deviceIdList, deviceId)
+		}
+
+		experimentsDetails.DeviceIds = strings.Join(deviceIdList, ",")
+	}
+
+	return nil
+}
+
+//DeviceStateCheck verifies that the target devices are attached to the vms and can be detached
+func DeviceStateCheck(ctx context.Context, experimentsDetails *experimentTypes.ExperimentDetails, handler DeviceHandler) error {
+
+	if experimentsDetails.DeviceIds == "" {
+		return errors.Errorf("no device id provided, please provide device id")
+	}
+
+	deviceIdList := strings.Split(experimentsDetails.DeviceIds, ",")
+	appVMMoidList := strings.Split(experimentsDetails.AppVMMoids, ",")
+	if len(deviceIdList) != len(appVMMoidList) {
+		return errors.Errorf("unequal number of device ids and vm ids found, please verify the input details")
+	}
+
+	for i := range deviceIdList {
+
+		attached, err := handler.IsAttached(ctx, appVMMoidList[i], deviceIdList[i])
+		if err != nil {
+			return errors.Errorf("failed to get the state of %v device, err: %v", deviceIdList[i], err)
+		}
+
+		if !attached {
+			return errors.Errorf("%v device is not attached to %v vm", deviceIdList[i], appVMMoidList[i])
+		}
+
+		if err = handler.PreCheck(ctx, appVMMoidList[i], deviceIdList[i]); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,343 @@
+package lib
+
+import (
+	"context"
+	"math/rand"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/chaosnative/litmus-go/pkg/cloud/vmware"
+	"github.com/chaosnative/litmus-go/pkg/vmware/abort"
+	experimentTypes "github.com/chaosnative/litmus-go/pkg/vmware/vmware-device-chaos/types"
+	"github.com/litmuschaos/litmus-go/pkg/clients"
+	"github.com/litmuschaos/litmus-go/pkg/events"
+	"github.com/litmuschaos/litmus-go/pkg/log"
+	"github.com/litmuschaos/litmus-go/pkg/probe"
+	"github.com/litmuschaos/litmus-go/pkg/types"
+	"github.com/litmuschaos/litmus-go/pkg/utils/common"
+	"github.com/pkg/errors"
+)
+
+var err error
+
+//detachedDevices contains the indices of the target devices which are detached and not reattached yet
+//it is updated by the chaos injection and read by the abort revert
+type detachedDevices struct {
+	mu      sync.Mutex
+	indices map[int]bool
+}
+
+func (d *detachedDevices) add(index int) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	d.indices[index] = true
+}
+
+func (d *detachedDevices) remove(index int) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	delete(d.indices, index)
+}
+
+func (d *detachedDevices) list() []int {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	var indexList []int
+	for index := range d.indices {
+		indexList = append(indexList, index)
+	}
+	sort.Ints(indexList)
+	return indexList
+}
+
+//PrepareDeviceChaos contains the prepration and injection steps for the experiment
+//the devices are detached and reattached using the given device handler
+//the chaos is reverted by the abort watcher, if an abort signal is received during the chaos injection
+func PrepareDeviceChaos(experimentsDetails *experimentTypes.ExperimentDetails, handler DeviceHandler, clients clients.ClientSets, resultDetails *types.ResultDetails, eventsDetails *types.EventDetails, chaosDetails *types.ChaosDetails, abortWatcher *abort.Watcher) error {
+
+	// ctx is cancelled when an abort signal is received, to interrupt the in-flight vcenter calls
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	//Waiting for the ramp time before chaos injection
+	if experimentsDetails.RampTime != 0 {
+		log.Infof("[Ramp]: Waiting for the %vs ramp time before injecting chaos", experimentsDetails.RampTime)
+		common.WaitForDuration(experimentsDetails.RampTime)
+	}
+
+	//get the device id list
+	deviceIdList := strings.Split(experimentsDetails.DeviceIds, ",")
+	if experimentsDetails.DeviceIds == "" {
+		return errors.Errorf("no device ids found to detach")
+	}
+
+	//get the vm id list
+	appVMMoidList := strings.Split(experimentsDetails.AppVMMoids, ",")
+	if experimentsDetails.AppVMMoids == "" {
+		return errors.Errorf("no vm ids found for corresponding devices")
+	}
+
+	if len(deviceIdList) != len(appVMMoidList) {
+		return errors.Errorf("unequal number of device ids and vm ids found")
+	}
+
+	//random generator used to select the target devices in every iteration
+	rng := NewRandomGenerator(experimentsDetails.DeviceAffectedPerc, experimentsDetails.RandomSeed)
+
+	//detached contains the devices which are reattached by the abort revert
+	detached := &detachedDevices{indices: map[int]bool{}}
+
+	// the abort watcher reverts the chaos, if an abort signal is received during the chaos injection
+	abortWatcher.Arm(cancel, func(ctx context.Context) {
+		revertChaos(ctx, experimentsDetails, appVMMoidList, deviceIdList, detached, handler, chaosDetails)
+	})
+
+	switch strings.ToLower(experimentsDetails.Sequence) {
+	case "serial":
+		err = injectChaosInSerialMode(ctx, experimentsDetails, appVMMoidList, deviceIdList, detached, handler, rng, clients, resultDetails, eventsDetails, chaosDetails)
+	case "parallel":
+		err = injectChaosInParallelMode(ctx, experimentsDetails, appVMMoidList, deviceIdList, detached, handler, rng, clients, resultDetails, eventsDetails, chaosDetails)
+	default:
+		err = errors.Errorf("%v sequence is not supported", experimentsDetails.Sequence)
+	}
+
+	if err != nil {
+		// the abort watcher reverts the chaos and exits, if the injection was interrupted by an abort signal
+		if ctx.Err() != nil {
+			abortWatcher.Wait()
+		}
+		return err
+	}
+
+	abortWatcher.Disarm()
+
+	//Waiting for the ramp time after chaos injection
+	if experimentsDetails.RampTime != 0 {
+		log.Infof("[Ramp]: Waiting for the %vs ramp time after injecting chaos", experimentsDetails.RampTime)
+		common.WaitForDuration(experimentsDetails.RampTime)
+	}
+	return nil
+}
+
+//injectChaosInSerialMode will inject the device chaos in serial mode which means one after the other
+func injectChaosInSerialMode(ctx context.Context, experimentsDetails *experimentTypes.ExperimentDetails, appVMMoidList []string, deviceIdList []string, detached *detachedDevices, handler DeviceHandler, rng *rand.Rand, clients clients.ClientSets, resultDetails *types.ResultDetails, eventsDetails *types.EventDetails, chaosDetails *types.ChaosDetails) error {
+
+	//ChaosStartTimeStamp contains the start timestamp, when the chaos injection begin
+	ChaosStartTimeStamp := time.Now()
+	duration := int(time.Since(ChaosStartTimeStamp).Seconds())
+
+	for duration < experimentsDetails.ChaosDuration {
+
+		if experimentsDetails.EngineName != "" {
+			msg := "Injecting " + experimentsDetails.ExperimentName + " chaos on VM"
+			types.SetEngineEventAttributes(eventsDetails, types.ChaosInject, msg, "Normal", chaosDetails)
+			events.GenerateEvents(eventsDetails, clients, chaosDetails, "ChaosEngine")
+		}
+
+		targetIndexList := selectTargets(experimentsDetails, deviceIdList, handler, rng, chaosDetails)
+
+		for j, i := range targetIndexList {
+
+			if err = detachDevice(ctx, appVMMoidList, deviceIdList, i, detached, handler, chaosDetails); err != nil {
+				return err
+			}
+
+			//Wait for device detachment
+			log.Infof("[Wait]: Wait for %s device detachment", deviceIdList[i])
+			if err = handler.WaitForDetach(ctx, appVMMoidList[i], deviceIdList[i], experimentsDetails.Delay, experimentsDetails.Timeout); err != nil {
+				return errors.Errorf("unable to detach %s device from the vm, err: %v", deviceIdList[i], err)
+			}
+
+			// run the probes during chaos
+			// the OnChaos probes execution will start in the first iteration and keep running for the entire chaos duration
+			if len(resultDetails.ProbeDetails) != 0 && j == 0 {
+				if err = probe.RunProbes(chaosDetails, clients, resultDetails, "DuringChaos", eventsDetails); err != nil {
+					return err
+				}
+			}
+
+			//Wait for chaos interval
+			log.Infof("[Wait]: Waiting for the chaos interval of %vs", experimentsDetails.ChaosInterval)
+			if err = vmware.WaitForDuration(ctx, experimentsDetails.ChaosInterval); err != nil {
+				return err
+			}
+
+			if err = attachDevice(ctx, experimentsDetails, appVMMoidList, deviceIdList, i, detached, handler, chaosDetails); err != nil {
+				return err
+			}
+		}
+		duration = int(time.Since(ChaosStartTimeStamp).Seconds())
+	}
+	return nil
+}
+
+//injectChaosInParallelMode will inject the device chaos in parallel mode that means all at once
+func injectChaosInParallelMode(ctx context.Context, experimentsDetails *experimentTypes.ExperimentDetails, appVMMoidList []string, deviceIdList []string, detached *detachedDevices, handler DeviceHandler, rng *rand.Rand, clients clients.ClientSets, resultDetails *types.ResultDetails, eventsDetails *types.EventDetails, chaosDetails *types.ChaosDetails) error {
+
+	//ChaosStartTimeStamp contains the start timestamp, when the chaos injection begin
+	ChaosStartTimeStamp := time.Now()
+	duration := int(time.Since(ChaosStartTimeStamp).Seconds())
+
+	for duration < experimentsDetails.ChaosDuration {
+
+		if experimentsDetails.EngineName != "" {
+			msg := "Injecting " + experimentsDetails.ExperimentName + " chaos on vm"
+			types.SetEngineEventAttributes(eventsDetails, types.ChaosInject, msg, "Normal", chaosDetails)
+			events.GenerateEvents(eventsDetails, clients, chaosDetails, "ChaosEngine")
+		}
+
+		targetIndexList := selectTargets(experimentsDetails, deviceIdList, handler, rng, chaosDetails)
+
+		for _, i := range targetIndexList {
+
+			if err = detachDevice(ctx, appVMMoidList, deviceIdList, i, detached, handler, chaosDetails); err != nil {
+				return err
+			}
+		}
+
+		for _, i := range targetIndexList {
+
+			//Wait for device detachment
+			log.Infof("[Wait]: Wait for %s device detachment", deviceIdList[i])
+			if err = handler.WaitForDetach(ctx, appVMMoidList[i], deviceIdList[i], experimentsDetails.Delay, experimentsDetails.Timeout); err != nil {
+				return errors.Errorf("unable to detach %s device from the vm, err: %v", deviceIdList[i], err)
+			}
+		}
+
+		// run the probes during chaos
+		if len(resultDetails.ProbeDetails) != 0 {
+			if err := probe.RunProbes(chaosDetails, clients, resultDetails, "DuringChaos", eventsDetails); err != nil {
+				return err
+			}
+		}
+
+		//Wait for chaos interval
+		log.Infof("[Wait]: Waiting for the chaos interval of %vs", experimentsDetails.ChaosInterval)
+		if err = vmware.WaitForDuration(ctx, experimentsDetails.ChaosInterval); err != nil {
+			return err
+		}
+
+		for _, i := range targetIndexList {
+
+			if err = attachDevice(ctx, experimentsDetails, appVMMoidList, deviceIdList, i, detached, handler, chaosDetails); err != nil {
+				return err
+			}
+		}
+		duration = int(time.Since(ChaosStartTimeStamp).Seconds())
+	}
+	return nil
+}
+
+//detachDevice captures the device details and detaches the device from the vm
+//the device is tracked before the call, as vcenter may detach the device even if the call fails or is interrupted
+func detachDevice(ctx context.Context, appVMMoidList, deviceIdList []string, index int, detached *detachedDevices, handler DeviceHandler, chaosDetails *types.ChaosDetails) error {
+
+	appVMMoid, deviceId := appVMMoidList[index], deviceIdList[index]
+
+	if err := handler.Prepare(ctx, appVMMoid, deviceId); err != nil {
+		return errors.Errorf("failed to capture %s device details before detachment, err: %v", deviceId, err)
+	}
+
+	detached.add(index)
+
+	//Detaching the device from the vm
+	log.Infof("[Chaos]: Detaching %s device from the vm", deviceId)
+	if err := handler.Detach(ctx, appVMMoid, deviceId); err != nil {
+		return errors.Errorf("%s device detachment failed, err: %v", deviceId, err)
+	}
+
+	common.SetTargets(deviceId, "injected", handler.Kind(), chaosDetails)
+	return nil
+}
+
+//attachDevice reattaches the device, if it is not already attached, and waits for the attachment
+func attachDevice(ctx context.Context, experimentsDetails *experimentTypes.ExperimentDetails, appVMMoidList, deviceIdList []string, index int, detached *detachedDevices, handler DeviceHandler, chaosDetails *types.ChaosDetails) error {
+
+	appVMMoid, deviceId := appVMMoidList[index], deviceIdList[index]
+
+	//Getting the device attachment status
+	attached, err := handler.IsAttached(ctx, appVMMoid, deviceId)
+	if err != nil {
+		return errors.Errorf("failed to get %s device status, err: %v", deviceId, err)
+	}
+
+	switch attached {
+	case true:
+		log.Infof("[Skip]: %s device is already attached", deviceId)
+	default:
+		//Attaching the device to the vm
+		log.Infof("[Chaos]: Attaching %s device to the VM", deviceId)
+		if err = handler.Attach(ctx, appVMMoid, deviceId); err != nil {
+			return errors.Errorf("%s device attachment failed, err: %v", deviceId, err)
+		}
+
+		//Wait for device attachment
+		log.Infof("[Wait]: Wait for %s device attachment", deviceId)
+		if err = handler.WaitForAttach(ctx, appVMMoid, deviceId, experimentsDetails.Delay, experimentsDetails.Timeout); err != nil {
+			return errors.Errorf("unable to attach %s device to the vm, err: %v", deviceId, err)
+		}
+	}
+
+	if err = handler.Release(ctx, appVMMoid, deviceId); err != nil {
+		return err
+	}
+
+	detached.remove(index)
+	common.SetTargets(deviceId, "reverted", handler.Kind(), chaosDetails)
+	return nil
+}
+
+//selectTargets selects the indices of the target devices for an iteration, based on the device affected percentage
+func selectTargets(experimentsDetails *experimentTypes.ExperimentDetails, deviceIdList []string, handler DeviceHandler, rng *rand.Rand, chaosDetails *types.ChaosDetails) []int {
+
+	targetIndexList := PickTargets(rng, len(deviceIdList), experimentsDetails.DeviceAffectedPerc)
+
+	var targetDeviceList []string
+	for _, i := range targetIndexList {
+		targetDeviceList = append(targetDeviceList, deviceIdList[i])
+		common.SetTargets(deviceIdList[i], "targeted", handler.Kind(), chaosDetails)
+	}
+
+	log.Infof("[Info]: Target %v device list for the iteration, %v", experimentsDetails.DeviceType, targetDeviceList)
+	return targetIndexList
+}
+
+//revertChaos reattaches the devices detached by the interrupted iteration, when an abort signal is received
+//the devices whose state is unknown are not reattached, the disks among them are reattached by the revert experiment using their recovery records
+func revertChaos(ctx context.Context, experimentsDetails *experimentTypes.ExperimentDetails, appVMMoidList, deviceIdList []string, detached *detachedDevices, handler DeviceHandler, chaosDetails *types.ChaosDetails) {
+
+	for _, i := range detached.list() {
+
+		attached, err := handler.IsAttached(ctx, appVMMoidList[i], deviceIdList[i])
+		if err != nil {
+			log.Errorf("failed to get %s device state when an abort signal is received, err: %v", deviceIdList[i], err)
+			continue
+		}
+
+		if !attached {
+
+			//We first wait for the device to get in detached state then we are attaching it.
+			log.Infof("[Abort]: Wait for complete device detachment for %s device", deviceIdList[i])
+			if err = handler.WaitForDetach(ctx, appVMMoidList[i], deviceIdList[i], experimentsDetails.Delay, experimentsDetails.Timeout); err != nil {
+				log.Errorf("unable to detach %s device, err: %v", deviceIdList[i], err)
+			}
+
+			//Attaching the device to the VM
+			log.Infof("[Abort]: Attaching %s device to the VM", deviceIdList[i])
+			if err = handler.Attach(ctx, appVMMoidList[i], deviceIdList[i]); err != nil {
+				log.Errorf("%s device attachment failed when an abort signal is received, err: %v", deviceIdList[i], err)
+				continue
+			}
+		}
+
+		if err = handler.Release(ctx, appVMMoidList[i], deviceIdList[i]); err != nil {
+			log.Errorf("failed to release %s device when an abort signal is received, err: %v", deviceIdList[i], err)
+		}
+
+		detached.remove(i)
+		common.SetTargets(deviceIdList[i], "reverted", handler.Kind(), chaosDetails)
+	}
+}
@@ -0,0 +1,66 @@
+package lib
+
+import (
+	"context"
+	"strings"
+
+	"github.com/chaosnative/litmus-go/pkg/cloud/vmware"
+	"github.com/chaosnative/litmus-go/pkg/vmware/recovery"
+	experimentTypes "github.com/chaosnative/litmus-go/pkg/vmware/vmware-disk-loss-revert/types"
+	"github.com/litmuschaos/litmus-go/pkg/clients"
+	"github.com/pkg/errors"
+)
+
+//ResolveTargetVMs derives the target vm moids from the provided vm moids, vm names or vm tags
+//it does not resolve the disks, as the target disks may be detached
+func ResolveTargetVMs(ctx context.Context, experimentsDetails *experimentTypes.ExperimentDetails, vcenterClient *vmware.VcenterClient) ([]string, error) {
+
+	switch {
+	case experimentsDetails.VMTags != "":
+		selectorList, err := vmware.ParseTagSelectors(experimentsDetails.VMTags)
+		if err != nil {
+			return nil, err
+		}
+		vmMoidList, err := vcenterClient.GetVMMoidsByTags(ctx, selectorList)
+		if err != nil {
+			return nil, errors.Errorf("failed to get the vms matching the tags, err: %v", err)
+		}
+		if len(vmMoidList) == 0 {
+			return nil, errors.Errorf("no vm found matching %v tags", experimentsDetails.VMTags)
+		}
+		return vmMoidList, nil
+	case experimentsDetails.AppVMNames != "":
+		appVMMoids, err := vcenterClient.GetVMMoids(ctx, experimentsDetails.AppVMNames)
+		if err != nil {
+			return nil, errors.Errorf("failed to resolve the vm names, err: %v", err)
+		}
+		return strings.Split(appVMMoids, ","), nil
+	case experimentsDetails.AppVMMoids != "":
+		return strings.Split(experimentsDetails.AppVMMoids, ","), nil
+	default:
+		return nil, nil
+	}
+}
+
+//ResolveRecoveryStores returns the recovery stores provided by RECOVERY_CONFIGMAPS
+//the recovery configmaps of all the experiments in the chaos namespace are returned, if none are provided
+func ResolveRecoveryStores(experimentsDetails *experimentTypes.ExperimentDetails, clients clients.ClientSets) ([]*recovery.Store, error) {
+
+	var nameList []string
+	if experimentsDetails.RecoveryConfigMaps != "" {
+		for _, name := range strings.Split(experimentsDetails.RecoveryConfigMaps, ",") {
+			nameList = append(nameList, strings.TrimSpace(name))
+		}
+	} else {
+		var err error
+		if nameList, err = recovery.ListStoreNames(clients, experimentsDetails.ChaosNamespace); err != nil {
+			return nil, err
+		}
+	}
+
+	var storeList []*recovery.Store
+	for _, name := range nameList {
+		storeList = append(storeList, recovery.NewStore(clients, experimentsDetails.ChaosNamespace, name))
+	}
+	return storeList, nil
+}
@@ -0,0 +1,118 @@
+package lib
+
+import (
+	"context"
+	"strings"
+
+	deviceLib "github.com/chaosnative/litmus-go/chaoslib/litmus/vmware-device-chaos/lib"
+	"github.com/chaosnative/litmus-go/pkg/cloud/vmware"
+	"github.com/chaosnative/litmus-go/pkg/vmware/recovery"
+	experimentTypes "github.com/chaosnative/litmus-go/pkg/vmware/vmware-disk-loss/types"
+	"github.com/litmuschaos/litmus-go/pkg/log"
+	"github.com/pkg/errors"
+	"github.com/sirupsen/logrus"
+)
+
+//PrintDiskLossPlan prints the detach and attach steps of every iteration without injecting the chaos
+//the target disks of an iteration are the same as the actual run only if the RANDOM_SEED is provided
+//the disks left detached by a previous run are listed, the actual run reattaches them before the chaos injection
+func PrintDiskLossPlan(ctx context.Context, experimentsDetails *experimentTypes.ExperimentDetails, store *recovery.Store, vcenterClient *vmware.VcenterClient) error {
+
+	recoveryList, err := store.List()
+	if err != nil {
+		return errors.Errorf("failed to list the disks detached by a previous run, err: %v", err)
+	}
+
+	for _, disk := range recoveryList {
+		log.InfoWithValues("[Dry Run]: Reattach the disk detached by a previous run", logrus.Fields{
+			"VM ID":       disk.VMMoid,
+			"Disk ID":     disk.DiskId,
+			"Disk Path":   disk.DiskPath,
+			"Detach Time": disk.DetachTime,
+		})
+	}
+
+	diskIdList := strings.Split(experimentsDetails.DiskIds, ",")
+	appVMMoidList := strings.Split(experimentsDetails.AppVMMoids, ",")
+	if len(diskIdList) != len(appVMMoidList) {
+		return errors.Errorf("unequal number of disk ids and vm ids found")
+	}
+
+	//get the disk details for the given disk ids
+	//the plan lists the disks without their details if they are not found, the disk status check reports them after the plan
+	var diskInfoList []vmware.DiskInfo
+	for i := range diskIdList {
+
+		diskInfo, err := vcenterClient.GetDiskInfo(ctx, appVMMoidList[i], diskIdList[i])
+		if err != nil {
+			log.Warnf("[Dry Run]: Failed to get the details of %v disk of %v vm, err: %v", diskIdList[i], appVMMoidList[i], err)
+			diskInfo = vmware.DiskInfo{Disk: diskIdList[i]}
+		}
+
+		diskInfoList = append(diskInfoList, diskInfo)
+	}
+
+	if experimentsDetails.RandomSeed == 0 && experimentsDetails.DiskAffectedPerc < 100 {
+		log.Warn("[Dry Run]: RANDOM_SEED is not provided, the actual run may select different target disks")
+	}
+
+	rng := deviceLib.NewRandomGenerator(experimentsDetails.DiskAffectedPerc, experimentsDetails.RandomSeed)
+	sequence := strings.ToLower(experimentsDetails.Sequence)
+	if sequence != "serial" && sequence != "parallel" {
+		return errors.Errorf("%v sequence is not supported", experimentsDetails.Sequence)
+	}
+
+	iterations := estimateIterations(experimentsDetails, len(diskIdList))
+	log.Infof("[Dry Run]: The chaos plan contains %v iterations", iterations)
+
+	for iteration := 1; iteration <= iterations; iteration++ {
+
+		targetIndexList := deviceLib.PickTargets(rng, len(diskIdList), experimentsDetails.DiskAffectedPerc)
+		log.Infof("[Dry Run]: Iteration %v of the %v chaos", iteration, sequence)
+
+		switch sequence {
+		case "serial":
+			for _, i := range targetIndexList {
+				printStep("Detach", appVMMoidList[i], diskInfoList[i])
+				log.Infof("[Dry Run]: Wait for the chaos interval of %vs", experimentsDetails.ChaosInterval)
+				printStep("Attach", appVMMoidList[i], diskInfoList[i])
+			}
+		default:
+			for _, i := range targetIndexList {
+				printStep("Detach", appVMMoidList[i], diskInfoList[i])
+			}
+			log.Infof("[Dry Run]: Wait for the chaos interval of %vs", experimentsDetails.ChaosInterval)
+			for _, i := range targetIndexList {
+				printStep("Attach", appVMMoidList[i], diskInfoList[i])
+			}
+		}
+	}
+
+	return nil
+}
+
+//estimateIterations returns the number of iterations that fit in the chaos duration
+//it ignores the time taken by the vcenter calls, so the actual run may have fewer iterations
+func estimateIterations(experimentsDetails *experimentTypes.ExperimentDetails, diskCount int) int {
+
+	iterationDuration := experimentsDetails.ChaosInterval
+	if strings.ToLower(experimentsDetails.Sequence) == "serial" {
+		iterationDuration = experimentsDetails.ChaosInterval * deviceLib.TargetCount(diskCount, experimentsDetails.DiskAffectedPerc)
+	}
+
+	if iterationDuration <= 0 || experimentsDetails.ChaosDuration <= iterationDuration {
+		return 1
+	}
+	return (experimentsDetails.ChaosDuration + iterationDuration - 1) / iterationDuration
+}
+
+//printStep prints a single detach or attach step of the plan
+func printStep(action, appVMMoid string, diskInfo vmware.DiskInfo) {
+	log.InfoWithValues("[Dry Run]: "+action+" the disk", logrus.Fields{
+		"VM ID":     appVMMoid,
+		"Disk ID":   diskInfo.Disk,
+		"Label":     diskInfo.Label,
+		"Position":  diskInfo.Position(),
+		"Disk Path": diskInfo.VMDKFile,
+	})
+}
@@ -0,0 +1,36 @@
+package lib
+
+import (
+	"context"
+	"strings"
+
+	"github.com/chaosnative/litmus-go/pkg/cloud/vmware"
+	"github.com/chaosnative/litmus-go/pkg/vmware/recovery"
+	experimentTypes "github.com/chaosnative/litmus-go/pkg/vmware/vmware-disk-loss/types"
+	"github.com/litmuschaos/litmus-go/pkg/clients"
+	"github.com/pkg/errors"
+)
+
+// NewRecoveryStore returns the recovery store for the given experiment
+func NewRecoveryStore(experimentsDetails *experimentTypes.ExperimentDetails, clients clients.ClientSets) *recovery.Store {
+	return recovery.NewStore(clients, experimentsDetails.ChaosNamespace, recovery.StoreName(experimentsDetails.EngineName, experimentsDetails.ExperimentName))
+}
+
+// RecoverDetachedDisks reattaches the disks left detached by a previous run of the experiment
+func RecoverDetachedDisks(ctx context.Context, experimentsDetails *experimentTypes.ExperimentDetails, store *recovery.Store, vcenterClient *vmware.VcenterClient) error {
+
+	report, err := recovery.RevertDetachedDisks(ctx, nil, store, vcenterClient, experimentsDetails.Delay, experimentsDetails.Timeout)
+	if err != nil {
+		return err
+	}
+
+	if len(report.Failed) != 0 {
+		var failedDisks []string
+		for _, disk := range report.Failed {
+			failedDisks = append(failedDisks, disk.DiskId)
+		}
+		return errors.Errorf("failed to reattach %v disks detached by a previous run", strings.Join(failedDisks, ","))
+	}
+
+	return nil
+}
@@ -0,0 +1,100 @@
+package lib
+
+import (
+	"context"
+	"strings"
+
+	"github.com/chaosnative/litmus-go/pkg/cloud/vmware"
+	experimentTypes "github.com/chaosnative/litmus-go/pkg/vmware/vmware-disk-loss/types"
+	"github.com/litmuschaos/litmus-go/pkg/log"
+	"github.com/pkg/errors"
+	"github.com/sirupsen/logrus"
+)
+
+//ResolveTargets derives the target vm moids and disk ids from the provided vm names, disk selectors or vm tags
+func ResolveTargets(ctx context.Context, experimentsDetails *experimentTypes.ExperimentDetails, vcenterClient *vmware.VcenterClient) error {
+	var err error
+
+	//select the vms and their non-boot disks using the vm tags
+	if experimentsDetails.VMTags != "" {
+		if experimentsDetails.AppVMMoids != "" || experimentsDetails.AppVMNames != "" || experimentsDetails.DiskIds != "" || experimentsDetails.DiskSelectors != "" {
+			return errors.Errorf("VM_TAGS can not be provided along with APP_VM_MOIDS, APP_VM_NAMES, VIRTUAL_DISK_IDS or VIRTUAL_DISK_SELECTORS")
+		}
+		return resolveTargetsByTags(ctx, experimentsDetails, vcenterClient)
+	}
+
+	//resolve the target vm names into the vm moids
+	if experimentsDetails.AppVMNames != "" {
+		if experimentsDetails.AppVMMoids != "" {
+			return errors.Errorf("both APP_VM_MOIDS and APP_VM_NAMES are provided, please provide only one of them")
+		}
+
+		if experimentsDetails.AppVMMoids, err = vcenterClient.GetVMMoids(ctx, experimentsDetails.AppVMNames); err != nil {
+			return errors.Errorf("failed to resolve the vm names, err: %v", err)
+		}
+	}
+
+	//resolve the disk selectors into the disk ids
+	if experimentsDetails.DiskSelectors != "" {
+		if experimentsDetails.DiskIds != "" {
+			return errors.Errorf("both VIRTUAL_DISK_IDS and VIRTUAL_DISK_SELECTORS are provided, please provide only one of them")
+		}
+
+		if experimentsDetails.DiskIds, err = vcenterClient.GetDiskIds(ctx, experimentsDetails.AppVMMoids, experimentsDetails.DiskSelectors); err != nil {
+			return errors.Errorf("failed to resolve the disk selectors, err: %v", err)
+		}
+	}
+
+	return nil
+}
+
+//resolveTargetsByTags selects all the non-boot disks of the vms matching the vm tags
+func resolveTargetsByTags(ctx context.Context, experimentsDetails *experimentTypes.ExperimentDetails, vcenterClient *vmware.VcenterClient) error {
+
+	selectorList, err := vmware.ParseTagSelectors(experimentsDetails.VMTags)
+	if err != nil {
+		return err
+	}
+
+	vmMoidList, err := vcenterClient.GetVMMoidsByTags(ctx, selectorList)
+	if err != nil {
+		return errors.Errorf("failed to get the vms matching the tags, err: %v", err)
+	}
+
+	if len(vmMoidList) == 0 {
+		return errors.Errorf("no vm found matching %v tags", experimentsDetails.VMTags)
+	}
+
+	var appVMMoidList, diskIdList []string
+	for _, vmMoid := range vmMoidList {
+
+		diskList, err := vcenterClient.GetNonBootDisks(ctx, vmMoid)
+		if err != nil {
+			return errors.Errorf("failed to get the disks of %v vm, err: %v", vmMoid, err)
+		}
+
+		if len(diskList) == 0 {
+			log.Warnf("[Skip]: %v vm does not have any non-boot disk", vmMoid)
+			continue
+		}
+
+		for _, disk := range diskList {
+			appVMMoidList = append(appVMMoidList, vmMoid)
+			diskIdList = append(diskIdList, disk.Disk)
+		}
+	}
+
+	if len(diskIdList) == 0 {
+		return errors.Errorf("no non-boot disk found in the vms matching %v tags", experimentsDetails.VMTags)
+	}
+
+	experimentsDetails.AppVMMoids = strings.Join(appVMMoidList, ",")
+	experimentsDetails.DiskIds = strings.Join(diskIdList, ",")
+
+	log.InfoWithValues("[Info]: The target disks selected using the vm tags are as follows", logrus.Fields{
+		"VM MOID":  experimentsDetails.AppVMMoids,
+		"Disk IDs": experimentsDetails.DiskIds,
+	})
+
+	return nil
+}
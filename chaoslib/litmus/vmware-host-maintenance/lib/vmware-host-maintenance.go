@@ -0,0 +1,175 @@
+package lib
+
+import (
+	"context"
+	"sync"
+
+	"github.com/chaosnative/litmus-go/pkg/cloud/vmware"
+	"github.com/chaosnative/litmus-go/pkg/vmware/abort"
+	experimentTypes "github.com/chaosnative/litmus-go/pkg/vmware/vmware-host-maintenance/types"
+	"github.com/litmuschaos/litmus-go/pkg/clients"
+	"github.com/litmuschaos/litmus-go/pkg/events"
+	"github.com/litmuschaos/litmus-go/pkg/log"
+	"github.com/litmuschaos/litmus-go/pkg/probe"
+	"github.com/litmuschaos/litmus-go/pkg/types"
+	"github.com/litmuschaos/litmus-go/pkg/utils/common"
+	"github.com/pkg/errors"
+)
+
+//enterTask contains the moid of the in-flight EnterMaintenanceMode_Task, which is cancelled by the revert
+//it is set by the chaos injection and read by the abort revert
+type enterTask struct {
+	mu   sync.Mutex
+	moid string
+}
+
+func (t *enterTask) set(moid string) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.moid = moid
+}
+
+func (t *enterTask) get() string {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	return t.moid
+}
+
+//PrepareHostMaintenance contains the prepration and injection steps for the experiment
+//the host is taken out of maintenance mode by the revert, whether the chaos injection completes, fails or is aborted
+func PrepareHostMaintenance(experimentsDetails *experimentTypes.ExperimentDetails, clients clients.ClientSets, resultDetails *types.ResultDetails, eventsDetails *types.EventDetails, chaosDetails *types.ChaosDetails, vcenterClient *vmware.VcenterClient, abortWatcher *abort.Watcher) error {
+
+	// ctx is cancelled when an abort signal is received, to interrupt the in-flight vcenter calls
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	//Waiting for the ramp time before chaos injection
+	if experimentsDetails.RampTime != 0 {
+		log.Infof("[Ramp]: Waiting for the %vs ramp time before injecting chaos", experimentsDetails.RampTime)
+		common.WaitForDuration(experimentsDetails.RampTime)
+	}
+
+	if experimentsDetails.HostMoid == "" {
+		return errors.Errorf("no host id found to put into maintenance mode")
+	}
+
+	task := &enterTask{}
+
+	// the abort watcher reverts the chaos, if an abort signal is received during the chaos injection
+	abortWatcher.Arm(cancel, func(ctx context.Context) {
+		if err := revertChaos(ctx, experimentsDetails, task, vcenterClient, chaosDetails); err != nil {
+			log.Errorf("failed to exit maintenance mode on %s host when an abort signal is received, err: %v", experimentsDetails.HostName, err)
+		}
+	})
+
+	if err := injectChaos(ctx, experimentsDetails, task, vcenterClient, clients, resultDetails, eventsDetails, chaosDetails); err != nil {
+		// the abort watcher reverts the chaos and exits, if the injection was interrupted by an abort signal
+		if ctx.Err() != nil {
+			abortWatcher.Wait()
+		}
+
+		abortWatcher.Disarm()
+		if revertErr := revertChaos(context.Background(), experimentsDetails, task, vcenterClient, chaosDetails); revertErr != nil {
+			return errors.Errorf("%v, failed to revert the chaos, err: %v", err, revertErr)
+		}
+		return err
+	}
+
+	abortWatcher.Disarm()
+
+	//Waiting for the ramp time after chaos injection
+	if experimentsDetails.RampTime != 0 {
+		log.Infof("[Ramp]: Waiting for the %vs ramp time after injecting chaos", experimentsDetails.RampTime)
+		common.WaitForDuration(experimentsDetails.RampTime)
+	}
+	return nil
+}
+
+//injectChaos puts the host into maintenance mode for the chaos duration and then takes it out of maintenance mode
+func injectChaos(ctx context.Context, experimentsDetails *experimentTypes.ExperimentDetails, task *enterTask, vcenterClient *vmware.VcenterClient, clients clients.ClientSets, resultDetails *types.ResultDetails, eventsDetails *types.EventDetails, chaosDetails *types.ChaosDetails) error {
+
+	if experimentsDetails.EngineName != "" {
+		msg := "Injecting " + experimentsDetails.ExperimentName + " chaos on host"
+		types.SetEngineEventAttributes(eventsDetails, types.ChaosInject, msg, "Normal", chaosDetails)
+		events.GenerateEvents(eventsDetails, clients, chaosDetails, "ChaosEngine")
+	}
+
+	//Putting the host into maintenance mode, the drs migrates the vms to the other hosts of the cluster
+	log.Infof("[Chaos]: Putting %s host into maintenance mode", experimentsDetails.HostName)
+	taskMoid, err := vcenterClient.EnterHostMaintenanceMode(ctx, experimentsDetails.HostMoid, experimentsDetails.EvacuatePoweredOffVMs, experimentsDetails.MaintenanceTimeout)
+	if err != nil {
+		return err
+	}
+	task.set(taskMoid)
+
+	common.SetTargets(experimentsDetails.HostName, "injected", "Host", chaosDetails)
+
+	//Wait for the host to enter maintenance mode
+	log.Infof("[Wait]: Wait for %s host to enter maintenance mode", experimentsDetails.HostName)
+	if err = vcenterClient.WaitForTask(ctx, taskMoid, experimentsDetails.Delay, experimentsDetails.MaintenanceTimeout); err != nil {
+		return errors.Errorf("%s host did not enter maintenance mode, err: %v", experimentsDetails.HostName, err)
+	}
+
+	// run the probes during chaos
+	if len(resultDetails.ProbeDetails) != 0 {
+		if err = probe.RunProbes(chaosDetails, clients, resultDetails, "DuringChaos", eventsDetails); err != nil {
+			return err
+		}
+	}
+
+	//Keeping the host in maintenance mode for the chaos duration
+	log.Infof("[Wait]: Waiting for the chaos duration of %vs", experimentsDetails.ChaosDuration)
+	if err = vmware.WaitForDuration(ctx, experimentsDetails.ChaosDuration); err != nil {
+		return err
+	}
+
+	//Taking the host out of maintenance mode
+	return exitMaintenanceMode(ctx, experimentsDetails, vcenterClient, chaosDetails)
+}
+
+//exitMaintenanceMode takes the host out of maintenance mode and waits for the task to complete
+func exitMaintenanceMode(ctx context.Context, experimentsDetails *experimentTypes.ExperimentDetails, vcenterClient *vmware.VcenterClient, chaosDetails *types.ChaosDetails) error {
+
+	log.Infof("[Chaos]: Taking %s host out of maintenance mode", experimentsDetails.HostName)
+	taskMoid, err := vcenterClient.ExitHostMaintenanceMode(ctx, experimentsDetails.HostMoid, experimentsDetails.MaintenanceTimeout)
+	if err != nil {
+		return err
+	}
+
+	log.Infof("[Wait]: Wait for %s host to exit maintenance mode", experimentsDetails.HostName)
+	if err = vcenterClient.WaitForTask(ctx, taskMoid, experimentsDetails.Delay, experimentsDetails.MaintenanceTimeout); err != nil {
+		return errors.Errorf("%s host did not exit maintenance mode, err: %v", experimentsDetails.HostName, err)
+	}
+
+	common.SetTargets(experimentsDetails.HostName, "reverted", "Host", chaosDetails)
+	return nil
+}
+
+//revertChaos takes the host out of maintenance mode, if it is in maintenance mode
+//the in-flight EnterMaintenanceMode_Task is cancelled first, so that the host does not enter maintenance mode after the revert
+func revertChaos(ctx context.Context, experimentsDetails *experimentTypes.ExperimentDetails, task *enterTask, vcenterClient *vmware.VcenterClient, chaosDetails *types.ChaosDetails) error {
+
+	if taskMoid := task.get(); taskMoid != "" {
+		if err := vcenterClient.CancelTask(ctx, taskMoid); err != nil {
+			log.Errorf("failed to cancel the maintenance mode task of %s host, err: %v", experimentsDetails.HostName, err)
+		}
+
+		// the cancelled task fails, the revert only waits for it to settle
+		if err := vcenterClient.WaitForTask(ctx, taskMoid, experimentsDetails.Delay, experimentsDetails.MaintenanceTimeout); err != nil {
+			log.Infof("[Info]: The maintenance mode task of %s host did not complete, err: %v", experimentsDetails.HostName, err)
+		}
+	}
+
+	inMaintenanceMode, err := vcenterClient.GetHostMaintenanceMode(ctx, experimentsDetails.HostMoid)
+	if err != nil {
+		log.Errorf("failed to get the maintenance mode of %s host, err: %v", experimentsDetails.HostName, err)
+	}
+
+	if err == nil && !inMaintenanceMode {
+		log.Infof("[Skip]: %s host is not in maintenance mode", experimentsDetails.HostName)
+		common.SetTargets(experimentsDetails.HostName, "reverted", "Host", chaosDetails)
+		return nil
+	}
+
+	return exitMaintenanceMode(ctx, experimentsDetails, vcenterClient, chaosDetails)
+}
@@ -0,0 +1,241 @@
+package lib
+
+import (
+	"context"
+	"sort"
+	"sync"
+
+	"github.com/chaosnative/litmus-go/pkg/cloud/vmware"
+	experimentTypes "github.com/chaosnative/litmus-go/pkg/vmware/vmware-vm-power/types"
+	"github.com/litmuschaos/litmus-go/pkg/log"
+	"github.com/pkg/errors"
+)
+
+const (
+	// PowerActionPowerOff powers off the vms
+	PowerActionPowerOff = "poweroff"
+	// PowerActionSuspend suspends the vms
+	PowerActionSuspend = "suspend"
+	// PowerActionGuest reboots or shuts down the guest os of the vms, as per the GUEST_POWER_ACTION env
+	PowerActionGuest = "guest"
+)
+
+//PowerAction takes the vms down and brings them back
+//the chaos lib is independent of the power action, a new power action only needs a new implementation
+type PowerAction interface {
+	//Name returns the name of the power action used in the logs, e.g, power off
+	Name() string
+	//PreCheck verifies that the powered on vms are ready for the power action
+	PreCheck(ctx context.Context, appVMMoids string) error
+	//Inject issues the power action to the vm
+	Inject(ctx context.Context, appVMMoid string) error
+	//WaitForInject waits for the vm to go down
+	WaitForInject(ctx context.Context, appVMMoid string, delay, timeout int) error
+	//IsInjected returns true if the vm is down and has to be brought back
+	IsInjected(ctx context.Context, appVMMoid string) (bool, error)
+	//Revert brings the vm back
+	Revert(ctx context.Context, appVMMoid string) error
+	//WaitForRevert waits for the vm to come back
+	WaitForRevert(ctx context.Context, appVMMoid string, delay, timeout int) error
+	//SelfReverting returns true if the vm comes back on its own, the chaos interval is then waited once the vm is back
+	SelfReverting() bool
+}
+
+//powerActions contains the constructors of the power actions for every supported power action
+var powerActions = map[string]func(experimentsDetails *experimentTypes.ExperimentDetails, vcenterClient *vmware.VcenterClient) (PowerAction, error){
+	PowerActionPowerOff: func(experimentsDetails *experimentTypes.ExperimentDetails, vcenterClient *vmware.VcenterClient) (PowerAction, error) {
+		return &powerOffAction{vmPowerAction{client: vcenterClient, waitForTools: experimentsDetails.WaitForTools}}, nil
+	},
+	PowerActionSuspend: func(experimentsDetails *experimentTypes.ExperimentDetails, vcenterClient *vmware.VcenterClient) (PowerAction, error) {
+		return &suspendAction{vmPowerAction{client: vcenterClient, waitForTools: experimentsDetails.WaitForTools}}, nil
+	},
+	PowerActionGuest: func(experimentsDetails *experimentTypes.ExperimentDetails, vcenterClient *vmware.VcenterClient) (PowerAction, error) {
+		switch experimentsDetails.GuestAction {
+		case vmware.GuestActionReboot, vmware.GuestActionShutdown:
+			return &guestAction{client: vcenterClient, action: experimentsDetails.GuestAction, bootInfo: map[string]vmware.GuestBootInfo{}}, nil
+		default:
+			return nil, errors.Errorf("%v guest power action is not supported, supported values are reboot and shutdown", experimentsDetails.GuestAction)
+		}
+	},
+}
+
+//NewPowerAction returns the power action of the experiment
+func NewPowerAction(experimentsDetails *experimentTypes.ExperimentDetails, vcenterClient *vmware.VcenterClient) (PowerAction, error) {
+	newAction, ok := powerActions[experimentsDetails.PowerAction]
+	if !ok {
+		return nil, errors.Errorf("%v power action is not supported, supported power actions are %v", experimentsDetails.PowerAction, SupportedPowerActions())
+	}
+	return newAction(experimentsDetails, vcenterClient)
+}
+
+//SupportedPowerActions returns the sorted list of the supported power actions
+func SupportedPowerActions() []string {
+	var powerActionList []string
+	for powerAction := range powerActions {
+		powerActionList = append(powerActionList, powerAction)
+	}
+	sort.Strings(powerActionList)
+	return powerActionList
+}
+
+//vmPowerAction contains the steps shared by the power actions which change the power state of the vm
+//the vm is brought back once it is in powered on state and, if required, its vmware tools are running
+type vmPowerAction struct {
+	client       *vmware.VcenterClient
+	waitForTools bool
+}
+
+func (a *vmPowerAction) PreCheck(ctx context.Context, appVMMoids string) error {
+	return nil
+}
+
+func (a *vmPowerAction) IsInjected(ctx context.Context, appVMMoid string) (bool, error) {
+	vmPowerState, err := a.client.GetVMPowerState(ctx, appVMMoid)
+	return vmPowerState != vmware.PowerStateOn, err
+}
+
+func (a *vmPowerAction) WaitForRevert(ctx context.Context, appVMMoid string, delay, timeout int) error {
+	if err := a.client.WaitForVMPowerState(ctx, appVMMoid, vmware.PowerStateOn, delay, timeout); err != nil {
+		return err
+	}
+
+	if !a.waitForTools {
+		return nil
+	}
+
+	if err := a.client.WaitForToolsRunning(ctx, appVMMoid, delay, timeout); err != nil {
+		return errors.Errorf("vmware tools are not running, err: %v", err)
+	}
+	return nil
+}
+
+func (a *vmPowerAction) SelfReverting() bool {
+	return false
+}
+
+//powerOffAction powers off the vm and powers it on
+type powerOffAction struct {
+	vmPowerAction
+}
+
+func (a *powerOffAction) Name() string {
+	return "power off"
+}
+
+func (a *powerOffAction) Inject(ctx context.Context, appVMMoid string) error {
+	return a.client.VMPowerOff(ctx, appVMMoid)
+}
+
+func (a *powerOffAction) WaitForInject(ctx context.Context, appVMMoid string, delay, timeout int) error {
+	return a.client.WaitForVMPowerState(ctx, appVMMoid, vmware.PowerStateOff, delay, timeout)
+}
+
+func (a *powerOffAction) Revert(ctx context.Context, appVMMoid string) error {
+	return a.client.VMPowerOn(ctx, appVMMoid)
+}
+
+//suspendAction suspends the vm and resumes it
+//the guest clock is stale right after the resume, until the vmware tools or ntp resynchronise it
+type suspendAction struct {
+	vmPowerAction
+}
+
+func (a *suspendAction) Name() string {
+	return "suspend"
+}
+
+func (a *suspendAction) Inject(ctx context.Context, appVMMoid string) error {
+	return a.client.VMSuspend(ctx, appVMMoid)
+}
+
+func (a *suspendAction) WaitForInject(ctx context.Context, appVMMoid string, delay, timeout int) error {
+	return a.client.WaitForVMPowerState(ctx, appVMMoid, vmware.PowerStateSuspended, delay, timeout)
+}
+
+func (a *suspendAction) Revert(ctx context.Context, appVMMoid string) error {
+	return a.client.VMResume(ctx, appVMMoid)
+}
+
+//guestAction reboots or shuts down the guest os of the vm using the vmware tools
+//the rebooting vm comes back on its own, whereas the shutdown vm is powered on
+type guestAction struct {
+	client *vmware.VcenterClient
+	action string
+
+	// mu guards the boot details of the vms captured before the injection, which are read by the chaos injection and the abort revert
+	mu       sync.Mutex
+	bootInfo map[string]vmware.GuestBootInfo
+}
+
+func (a *guestAction) Name() string {
+	return "guest " + a.action
+}
+
+//PreCheck verifies that the guest os of the vms are running, as the guest power action requires the vmware tools
+func (a *guestAction) PreCheck(ctx context.Context, appVMMoids string) error {
+	return a.client.GuestHeartbeatCheck(ctx, appVMMoids)
+}
+
+//Inject captures the boot details of the vm before issuing the guest power action, so that a quick reboot can be detected
+func (a *guestAction) Inject(ctx context.Context, appVMMoid string) error {
+
+	bootInfo, err := a.client.GetGuestBootInfo(ctx, appVMMoid)
+	if err != nil {
+		log.Warnf("[Warning]: %v, only the guest heartbeat loss is verified", err)
+	} else {
+		a.mu.Lock()
+		a.bootInfo[appVMMoid] = bootInfo
+		a.mu.Unlock()
+	}
+
+	return a.client.GuestPowerAction(ctx, appVMMoid, a.action)
+}
+
+//WaitForInject waits for the guest heartbeat to be lost or the guest os to reboot, and for the vm to power off in case of shutdown
+func (a *guestAction) WaitForInject(ctx context.Context, appVMMoid string, delay, timeout int) error {
+	if err := a.client.WaitForGuestHeartbeatLoss(ctx, appVMMoid, a.baseline(appVMMoid), delay, timeout); err != nil {
+		return err
+	}
+
+	if a.action != vmware.GuestActionShutdown {
+		return nil
+	}
+	return a.client.WaitForVMPowerState(ctx, appVMMoid, vmware.PowerStateOff, delay, timeout)
+}
+
+//IsInjected returns true if the shutdown vm is not powered on yet, the rebooting vm comes back on its own
+func (a *guestAction) IsInjected(ctx context.Context, appVMMoid string) (bool, error) {
+	if a.SelfReverting() {
+		return false, nil
+	}
+
+	vmPowerState, err := a.client.GetVMPowerState(ctx, appVMMoid)
+	return vmPowerState != vmware.PowerStateOn, err
+}
+
+func (a *guestAction) Revert(ctx context.Context, appVMMoid string) error {
+	if a.SelfReverting() {
+		return nil
+	}
+	return a.client.VMPowerOn(ctx, appVMMoid)
+}
+
+func (a *guestAction) WaitForRevert(ctx context.Context, appVMMoid string, delay, timeout int) error {
+	return a.client.WaitForGuestHeartbeat(ctx, appVMMoid, delay, timeout)
+}
+
+func (a *guestAction) SelfReverting() bool {
+	return a.action == vmware.GuestActionReboot
+}
+
+//baseline returns the boot details of the vm captured before the injection, or nil if they are not captured
+func (a *guestAction) baseline(appVMMoid string) *vmware.GuestBootInfo {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	bootInfo, ok := a.bootInfo[appVMMoid]
+	if !ok {
+		return nil
+	}
+	return &bootInfo
+}
@@ -0,0 +1,92 @@
+package lib
+
+import (
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/litmuschaos/litmus-go/pkg/clients"
+	"github.com/litmuschaos/litmus-go/pkg/log"
+	"github.com/litmuschaos/litmus-go/pkg/types"
+	"github.com/litmuschaos/litmus-go/pkg/utils/retry"
+	"github.com/pkg/errors"
+	"github.com/sirupsen/logrus"
+	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// downtimeAnnotationPrefix is the prefix of the chaosresult annotations containing the downtime of the vms
+const downtimeAnnotationPrefix = "downtime.litmuschaos.io/"
+
+// VMDowntime contains the downtime observed for a vm
+type VMDowntime struct {
+	Total time.Duration
+	Max   time.Duration
+	Count int
+}
+
+// String returns the downtime in the total, max and count form
+func (downtime VMDowntime) String() string {
+	return fmt.Sprintf("total: %v, max: %v, count: %v", downtime.Total, downtime.Max, downtime.Count)
+}
+
+// DowntimeTracker tracks the downtime observed for every vm during the chaos
+type DowntimeTracker map[string]*VMDowntime
+
+// Add adds the downtime observed for the given vm
+func (tracker DowntimeTracker) Add(appVMMoid string, downtime time.Duration) {
+
+	downtime = downtime.Round(time.Second)
+	log.Infof("[Info]: Observed %v downtime for %v vm", downtime, appVMMoid)
+
+	vmDowntime, ok := tracker[appVMMoid]
+	if !ok {
+		vmDowntime = &VMDowntime{}
+		tracker[appVMMoid] = vmDowntime
+	}
+
+	vmDowntime.Total += downtime
+	vmDowntime.Count++
+	if downtime > vmDowntime.Max {
+		vmDowntime.Max = downtime
+	}
+}
+
+// Record annotates the chaosresult with the downtime observed for every vm
+func (tracker DowntimeTracker) Record(clients clients.ClientSets, resultDetails *types.ResultDetails, chaosDetails *types.ChaosDetails) error {
+
+	if len(tracker) == 0 {
+		return nil
+	}
+
+	var appVMMoidList []string
+	fields := logrus.Fields{}
+	for appVMMoid, vmDowntime := range tracker {
+		appVMMoidList = append(appVMMoidList, appVMMoid)
+		fields[appVMMoid] = vmDowntime.String()
+	}
+	sort.Strings(appVMMoidList)
+	log.InfoWithValues("[Info]: The downtime observed for the vms is as follows", fields)
+
+	return retry.
+		Times(90).
+		Wait(2 * time.Second).
+		Try(func(attempt uint) error {
+
+			result, err := clients.LitmusClient.ChaosResults(chaosDetails.ChaosNamespace).Get(resultDetails.Name, v1.GetOptions{})
+			if err != nil {
+				return errors.Errorf("unable to get the %v chaosresult, err: %v", resultDetails.Name, err)
+			}
+
+			if result.Annotations == nil {
+				result.Annotations = map[string]string{}
+			}
+			for _, appVMMoid := range appVMMoidList {
+				result.Annotations[downtimeAnnotationPrefix+appVMMoid] = tracker[appVMMoid].String()
+			}
+
+			if _, err = clients.LitmusClient.ChaosResults(chaosDetails.ChaosNamespace).Update(result); err != nil {
+				return errors.Errorf("unable to update the %v chaosresult, err: %v", resultDetails.Name, err)
+			}
+			return nil
+		})
+}
@@ -0,0 +1,50 @@
+package lib
+
+import (
+	"context"
+	"strings"
+
+	"github.com/chaosnative/litmus-go/pkg/cloud/vmware"
+	experimentTypes "github.com/chaosnative/litmus-go/pkg/vmware/vmware-vm-power/types"
+	"github.com/litmuschaos/litmus-go/pkg/log"
+	"github.com/pkg/errors"
+)
+
+//ResolveTargets derives the target vm moids from the provided vm names or vm tags
+func ResolveTargets(ctx context.Context, experimentsDetails *experimentTypes.ExperimentDetails, vcenterClient *vmware.VcenterClient) error {
+	var err error
+
+	switch {
+	case experimentsDetails.VMTags != "":
+		if experimentsDetails.AppVMMoids != "" || experimentsDetails.AppVMNames != "" {
+			return errors.Errorf("VM_TAGS can not be provided along with APP_VM_MOIDS or APP_VM_NAMES")
+		}
+
+		selectorList, err := vmware.ParseTagSelectors(experimentsDetails.VMTags)
+		if err != nil {
+			return err
+		}
+
+		vmMoidList, err := vcenterClient.GetVMMoidsByTags(ctx, selectorList)
+		if err != nil {
+			return errors.Errorf("failed to get the vms matching the tags, err: %v", err)
+		}
+
+		if len(vmMoidList) == 0 {
+			return errors.Errorf("no vm found matching %v tags", experimentsDetails.VMTags)
+		}
+
+		experimentsDetails.AppVMMoids = strings.Join(vmMoidList, ",")
+		log.Infof("[Info]: The target vms selected using the vm tags are %v", experimentsDetails.AppVMMoids)
+	case experimentsDetails.AppVMNames != "":
+		if experimentsDetails.AppVMMoids != "" {
+			return errors.Errorf("both APP_VM_MOIDS and APP_VM_NAMES are provided, please provide only one of them")
+		}
+
+		if experimentsDetails.AppVMMoids, err = vcenterClient.GetVMMoids(ctx, experimentsDetails.AppVMNames); err != nil {
+			return errors.Errorf("failed to resolve the vm names, err: %v", err)
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,353 @@
+package lib
+
+import (
+	"context"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/chaosnative/litmus-go/pkg/cloud/vmware"
+	"github.com/chaosnative/litmus-go/pkg/vmware/abort"
+	experimentTypes "github.com/chaosnative/litmus-go/pkg/vmware/vmware-vm-power/types"
+	"github.com/litmuschaos/litmus-go/pkg/clients"
+	"github.com/litmuschaos/litmus-go/pkg/events"
+	"github.com/litmuschaos/litmus-go/pkg/log"
+	"github.com/litmuschaos/litmus-go/pkg/probe"
+	"github.com/litmuschaos/litmus-go/pkg/types"
+	"github.com/litmuschaos/litmus-go/pkg/utils/common"
+	"github.com/pkg/errors"
+)
+
+var err error
+
+//issuedActions contains the vms to which the power action is issued and which are not brought back yet
+//it is updated by the chaos injection and read by the abort revert
+type issuedActions struct {
+	mu         sync.Mutex
+	appVMMoids map[string]bool
+}
+
+func (a *issuedActions) add(appVMMoid string) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	a.appVMMoids[appVMMoid] = true
+}
+
+func (a *issuedActions) remove(appVMMoid string) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	delete(a.appVMMoids, appVMMoid)
+}
+
+func (a *issuedActions) list() []string {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	var appVMMoidList []string
+	for appVMMoid := range a.appVMMoids {
+		appVMMoidList = append(appVMMoidList, appVMMoid)
+	}
+	sort.Strings(appVMMoidList)
+	return appVMMoidList
+}
+
+//PrepareVMPowerChaos contains the prepration and injection steps for the experiment
+//the chaos is reverted by the abort watcher, if an abort signal is received during the chaos injection
+func PrepareVMPowerChaos(experimentsDetails *experimentTypes.ExperimentDetails, action PowerAction, clients clients.ClientSets, resultDetails *types.ResultDetails, eventsDetails *types.EventDetails, chaosDetails *types.ChaosDetails, abortWatcher *abort.Watcher) error {
+
+	// ctx is cancelled when an abort signal is received, to interrupt the in-flight vcenter calls
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	//Waiting for the ramp time before chaos injection
+	if experimentsDetails.RampTime != 0 {
+		log.Infof("[Ramp]: Waiting for the %vs ramp time before injecting chaos", experimentsDetails.RampTime)
+		common.WaitForDuration(experimentsDetails.RampTime)
+	}
+
+	//get the vm id list
+	appVMMoidList := strings.Split(experimentsDetails.AppVMMoids, ",")
+	if experimentsDetails.AppVMMoids == "" {
+		return errors.Errorf("no vm ids found to %v", action.Name())
+	}
+
+	//downtime tracker contains the downtime observed for every vm
+	downtime := DowntimeTracker{}
+
+	//issued contains the vms which are brought back by the abort revert
+	issued := &issuedActions{appVMMoids: map[string]bool{}}
+
+	// the abort watcher reverts the chaos, if an abort signal is received during the chaos injection
+	abortWatcher.Arm(cancel, func(ctx context.Context) {
+		revertChaos(ctx, experimentsDetails, action, issued, chaosDetails)
+	})
+
+	switch strings.ToLower(experimentsDetails.Sequence) {
+	case "serial":
+		err = injectChaosInSerialMode(ctx, experimentsDetails, action, appVMMoidList, issued, downtime, clients, resultDetails, eventsDetails, chaosDetails)
+	case "parallel":
+		err = injectChaosInParallelMode(ctx, experimentsDetails, action, appVMMoidList, issued, downtime, clients, resultDetails, eventsDetails, chaosDetails)
+	default:
+		err = errors.Errorf("%v sequence is not supported", experimentsDetails.Sequence)
+	}
+
+	if err != nil {
+		// the abort watcher reverts the chaos and exits, if the injection was interrupted by an abort signal
+		if ctx.Err() != nil {
+			abortWatcher.Wait()
+		}
+		return err
+	}
+
+	abortWatcher.Disarm()
+
+	//Recording the downtime observed for the vms in the chaosresult
+	if err = downtime.Record(clients, resultDetails, chaosDetails); err != nil {
+		return errors.Errorf("failed to record the vm downtime, err: %v", err)
+	}
+
+	//Waiting for the ramp time after chaos injection
+	if experimentsDetails.RampTime != 0 {
+		log.Infof("[Ramp]: Waiting for the %vs ramp time after injecting chaos", experimentsDetails.RampTime)
+		common.WaitForDuration(experimentsDetails.RampTime)
+	}
+	return nil
+}
+
+//injectChaosInSerialMode will inject the power action on the vms in serial mode which means one after the other
+func injectChaosInSerialMode(ctx context.Context, experimentsDetails *experimentTypes.ExperimentDetails, action PowerAction, appVMMoidList []string, issued *issuedActions, downtime DowntimeTracker, clients clients.ClientSets, resultDetails *types.ResultDetails, eventsDetails *types.EventDetails, chaosDetails *types.ChaosDetails) error {
+
+	//ChaosStartTimeStamp contains the start timestamp, when the chaos injection begin
+	ChaosStartTimeStamp := time.Now()
+	duration := int(time.Since(ChaosStartTimeStamp).Seconds())
+
+	for duration < experimentsDetails.ChaosDuration {
+
+		log.Infof("[Info]: Target VM Id list, %v", appVMMoidList)
+
+		if experimentsDetails.EngineName != "" {
+			msg := "Injecting " + experimentsDetails.ExperimentName + " chaos on VM"
+			types.SetEngineEventAttributes(eventsDetails, types.ChaosInject, msg, "Normal", chaosDetails)
+			events.GenerateEvents(eventsDetails, clients, chaosDetails, "ChaosEngine")
+		}
+
+		for i, appVMMoid := range appVMMoidList {
+
+			//Taking the vm down
+			injectTime := time.Now()
+			if err = injectPowerAction(ctx, action, appVMMoid, issued, chaosDetails); err != nil {
+				return err
+			}
+			if err = waitForVMDown(ctx, experimentsDetails, action, appVMMoid); err != nil {
+				return err
+			}
+
+			// run the probes during chaos
+			// the OnChaos probes execution will start in the first iteration and keep running for the entire chaos duration
+			if len(resultDetails.ProbeDetails) != 0 && i == 0 {
+				if err = probe.RunProbes(chaosDetails, clients, resultDetails, "DuringChaos", eventsDetails); err != nil {
+					return err
+				}
+			}
+
+			//Keeping the vm down for the chaos interval, unless it comes back on its own
+			if !action.SelfReverting() {
+				if err = waitForChaosInterval(ctx, experimentsDetails); err != nil {
+					return err
+				}
+			}
+
+			//Bringing the vm back
+			if err = revertPowerAction(ctx, action, appVMMoid); err != nil {
+				return err
+			}
+			if err = waitForVMBack(ctx, experimentsDetails, action, appVMMoid, injectTime, issued, downtime, chaosDetails); err != nil {
+				return err
+			}
+
+			//Waiting for the chaos interval before the next injection, if the vm came back on its own
+			if action.SelfReverting() {
+				if err = waitForChaosInterval(ctx, experimentsDetails); err != nil {
+					return err
+				}
+			}
+		}
+		duration = int(time.Since(ChaosStartTimeStamp).Seconds())
+	}
+	return nil
+}
+
+//injectChaosInParallelMode will inject the power action on the vms in parallel mode that means all at once
+func injectChaosInParallelMode(ctx context.Context, experimentsDetails *experimentTypes.ExperimentDetails, action PowerAction, appVMMoidList []string, issued *issuedActions, downtime DowntimeTracker, clients clients.ClientSets, resultDetails *types.ResultDetails, eventsDetails *types.EventDetails, chaosDetails *types.ChaosDetails) error {
+
+	//ChaosStartTimeStamp contains the start timestamp, when the chaos injection begin
+	ChaosStartTimeStamp := time.Now()
+	duration := int(time.Since(ChaosStartTimeStamp).Seconds())
+
+	for duration < experimentsDetails.ChaosDuration {
+
+		log.Infof("[Info]: Target VM Id list, %v", appVMMoidList)
+
+		if experimentsDetails.EngineName != "" {
+			msg := "Injecting " + experimentsDetails.ExperimentName + " chaos on vm"
+			types.SetEngineEventAttributes(eventsDetails, types.ChaosInject, msg, "Normal", chaosDetails)
+			events.GenerateEvents(eventsDetails, clients, chaosDetails, "ChaosEngine")
+		}
+
+		injectTimes := map[string]time.Time{}
+		for _, appVMMoid := range appVMMoidList {
+
+			//Taking the vm down
+			injectTimes[appVMMoid] = time.Now()
+			if err = injectPowerAction(ctx, action, appVMMoid, issued, chaosDetails); err != nil {
+				return err
+			}
+		}
+
+		for _, appVMMoid := range appVMMoidList {
+			if err = waitForVMDown(ctx, experimentsDetails, action, appVMMoid); err != nil {
+				return err
+			}
+		}
+
+		// run the probes during chaos
+		if len(resultDetails.ProbeDetails) != 0 {
+			if err := probe.RunProbes(chaosDetails, clients, resultDetails, "DuringChaos", eventsDetails); err != nil {
+				return err
+			}
+		}
+
+		//Keeping the vms down for the chaos interval, unless they come back on their own
+		if !action.SelfReverting() {
+			if err = waitForChaosInterval(ctx, experimentsDetails); err != nil {
+				return err
+			}
+		}
+
+		for _, appVMMoid := range appVMMoidList {
+
+			//Bringing the vm back
+			if err = revertPowerAction(ctx, action, appVMMoid); err != nil {
+				return err
+			}
+		}
+
+		for _, appVMMoid := range appVMMoidList {
+			if err = waitForVMBack(ctx, experimentsDetails, action, appVMMoid, injectTimes[appVMMoid], issued, downtime, chaosDetails); err != nil {
+				return err
+			}
+		}
+
+		//Waiting for the chaos interval before the next injection, if the vms came back on their own
+		if action.SelfReverting() {
+			if err = waitForChaosInterval(ctx, experimentsDetails); err != nil {
+				return err
+			}
+		}
+		duration = int(time.Since(ChaosStartTimeStamp).Seconds())
+	}
+	return nil
+}
+
+//injectPowerAction issues the power action to the vm
+//the vm is tracked before the call, as vcenter may accept the power action even if the call fails or is interrupted
+func injectPowerAction(ctx context.Context, action PowerAction, appVMMoid string, issued *issuedActions, chaosDetails *types.ChaosDetails) error {
+
+	issued.add(appVMMoid)
+
+	log.Infof("[Chaos]: Issuing %s to %s vm", action.Name(), appVMMoid)
+	if err := action.Inject(ctx, appVMMoid); err != nil {
+		return errors.Errorf("failed to %s %s vm, err: %v", action.Name(), appVMMoid, err)
+	}
+
+	common.SetTargets(appVMMoid, "injected", "VM", chaosDetails)
+	return nil
+}
+
+//waitForVMDown waits for the vm to go down after the power action
+func waitForVMDown(ctx context.Context, experimentsDetails *experimentTypes.ExperimentDetails, action PowerAction, appVMMoid string) error {
+
+	log.Infof("[Wait]: Wait for %s vm to go down", appVMMoid)
+	if err := action.WaitForInject(ctx, appVMMoid, experimentsDetails.Delay, experimentsDetails.Timeout); err != nil {
+		return errors.Errorf("%s vm did not go down after the %s, err: %v", appVMMoid, action.Name(), err)
+	}
+	return nil
+}
+
+//revertPowerAction brings the vm back, unless it comes back on its own
+func revertPowerAction(ctx context.Context, action PowerAction, appVMMoid string) error {
+
+	if action.SelfReverting() {
+		return nil
+	}
+
+	log.Infof("[Chaos]: Reverting the %s of %s vm", action.Name(), appVMMoid)
+	if err := action.Revert(ctx, appVMMoid); err != nil {
+		return errors.Errorf("failed to revert the %s of %s vm, err: %v", action.Name(), appVMMoid, err)
+	}
+	return nil
+}
+
+//waitForVMBack waits for the vm to come back and records the observed downtime
+func waitForVMBack(ctx context.Context, experimentsDetails *experimentTypes.ExperimentDetails, action PowerAction, appVMMoid string, injectTime time.Time, issued *issuedActions, downtime DowntimeTracker, chaosDetails *types.ChaosDetails) error {
+
+	log.Infof("[Wait]: Wait for %s vm to come back", appVMMoid)
+	if err := action.WaitForRevert(ctx, appVMMoid, experimentsDetails.Delay, experimentsDetails.Timeout); err != nil {
+		return errors.Errorf("%s vm did not come back after the %s, err: %v", appVMMoid, action.Name(), err)
+	}
+
+	downtime.Add(appVMMoid, time.Since(injectTime))
+	issued.remove(appVMMoid)
+	common.SetTargets(appVMMoid, "reverted", "VM", chaosDetails)
+	return nil
+}
+
+//waitForChaosInterval waits for the chaos interval
+func waitForChaosInterval(ctx context.Context, experimentsDetails *experimentTypes.ExperimentDetails) error {
+	log.Infof("[Wait]: Waiting for the chaos interval of %vs", experimentsDetails.ChaosInterval)
+	return vmware.WaitForDuration(ctx, experimentsDetails.ChaosInterval)
+}
+
+//revertChaos brings back the vms to which the power action is issued, when an abort signal is received
+//it waits for every vm to go down first, within the status check timeout, so that the in-flight power action does not race with the revert
+func revertChaos(ctx context.Context, experimentsDetails *experimentTypes.ExperimentDetails, action PowerAction, issued *issuedActions, chaosDetails *types.ChaosDetails) {
+
+	for _, appVMMoid := range issued.list() {
+
+		//the vm comes back on its own, once the power action takes effect
+		if action.SelfReverting() {
+			log.Infof("[Abort]: Wait for %s vm to come back", appVMMoid)
+			if err := action.WaitForRevert(ctx, appVMMoid, experimentsDetails.Delay, experimentsDetails.Timeout); err != nil {
+				log.Errorf("%s vm did not come back after the %s when an abort signal is received, err: %v", appVMMoid, action.Name(), err)
+				continue
+			}
+
+			issued.remove(appVMMoid)
+			common.SetTargets(appVMMoid, "reverted", "VM", chaosDetails)
+			continue
+		}
+
+		log.Infof("[Abort]: Wait for %s vm to go down", appVMMoid)
+		if err := action.WaitForInject(ctx, appVMMoid, experimentsDetails.Delay, experimentsDetails.Timeout); err != nil {
+			log.Infof("[Info]: %s vm did not go down after the %s, err: %v", appVMMoid, action.Name(), err)
+		}
+
+		injected, err := action.IsInjected(ctx, appVMMoid)
+		if err != nil {
+			log.Errorf("failed to get the state of %s vm when an abort signal is received, err: %v", appVMMoid, err)
+			continue
+		}
+
+		if injected {
+			log.Infof("[Abort]: Reverting the %s of %s vm", action.Name(), appVMMoid)
+			if err := action.Revert(ctx, appVMMoid); err != nil {
+				log.Errorf("failed to revert the %s of %s vm when an abort signal is received, err: %v", action.Name(), appVMMoid, err)
+				continue
+			}
+		}
+
+		issued.remove(appVMMoid)
+		common.SetTargets(appVMMoid, "reverted", "VM", chaosDetails)
+	}
+}
@@ -0,0 +1,13 @@
+package experiment
+
+import (
+	"github.com/chaosnative/litmus-go/pkg/cloud/vmware"
+	"github.com/chaosnative/litmus-go/pkg/vmware/vmware-device-chaos/runner"
+	clients "github.com/litmuschaos/litmus-go/pkg/clients"
+)
+
+// VMWareCDROMDisconnect contains steps to inject chaos
+// it disconnects the cd-rom devices (and the mounted iso images) of the vms using the device chaos lib
+func VMWareCDROMDisconnect(clients clients.ClientSets) {
+	runner.DeviceChaos(clients, "vmware-cdrom-disconnect", vmware.DeviceTypeCdrom)
+}
@@ -0,0 +1,12 @@
+package experiment
+
+import (
+	"github.com/chaosnative/litmus-go/pkg/vmware/vmware-device-chaos/runner"
+	clients "github.com/litmuschaos/litmus-go/pkg/clients"
+)
+
+// VMWareDeviceDisconnect contains steps to inject chaos on the virtual devices of the type provided by DEVICE_TYPE
+// the connectable devices are disconnected in place, whereas the disks are detached and reattached
+func VMWareDeviceDisconnect(clients clients.ClientSets) {
+	runner.DeviceChaos(clients, "vmware-device-disconnect", "")
+}
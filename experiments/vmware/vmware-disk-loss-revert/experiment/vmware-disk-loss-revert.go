@@ -0,0 +1,145 @@
+package experiment
+
+import (
+	"context"
+	"os"
+	"strings"
+
+	litmusLIB "github.com/chaosnative/litmus-go/chaoslib/litmus/vmware-disk-loss-revert/lib"
+	"github.com/chaosnative/litmus-go/pkg/vmware/recovery"
+	"github.com/chaosnative/litmus-go/pkg/vmware/vcenter"
+	experimentEnv "github.com/chaosnative/litmus-go/pkg/vmware/vmware-disk-loss-revert/environment"
+	experimentTypes "github.com/chaosnative/litmus-go/pkg/vmware/vmware-disk-loss-revert/types"
+	"github.com/litmuschaos/chaos-operator/pkg/apis/litmuschaos/v1alpha1"
+	clients "github.com/litmuschaos/litmus-go/pkg/clients"
+	"github.com/litmuschaos/litmus-go/pkg/log"
+	"github.com/litmuschaos/litmus-go/pkg/result"
+	"github.com/litmuschaos/litmus-go/pkg/types"
+	"github.com/pkg/errors"
+	"github.com/sirupsen/logrus"
+)
+
+// VMWareDiskLossRevert reattaches the disks left detached by a crashed run of the experiments detaching the disks
+// it exits with a non-zero code and records the failure in the chaos result, if any of the disks is not reattached
+func VMWareDiskLossRevert(clients clients.ClientSets) {
+	var err error
+	experimentsDetails := experimentTypes.ExperimentDetails{}
+	resultDetails := types.ResultDetails{}
+	eventsDetails := types.EventDetails{}
+	chaosDetails := types.ChaosDetails{}
+
+	//Fetching all the ENV passed from the runner pod
+	experimentEnv.GetENV(&experimentsDetails)
+	log.Infof("[PreReq]: Procured the ENV for the %v experiment", experimentsDetails.ExperimentName)
+
+	// Intialize the chaos attributes
+	// the revert records its own chaos result, independent of the experiment whose disks are reverted
+	types.InitialiseChaosVariables(&chaosDetails)
+	chaosDetails.ExperimentName = experimentsDetails.ExperimentName
+
+	// Intialize Chaos Result Parameters
+	types.SetResultAttributes(&resultDetails, chaosDetails)
+
+	//Updating the chaos result in the beginning of the revert
+	log.Infof("[PreReq]: Updating the chaos result of %v experiment (SOT)", experimentsDetails.ExperimentName)
+	if err = result.ChaosResult(&chaosDetails, clients, &resultDetails, "SOT"); err != nil {
+		log.Errorf("Unable to Create the Chaos Result, err: %v", err)
+		os.Exit(1)
+	}
+
+	// Set the chaos result uid
+	result.SetResultUID(&resultDetails, clients, &chaosDetails)
+
+	if failStep, err := revertDisks(&experimentsDetails, clients); err != nil {
+		log.Errorf("Disk revert failed, err: %v", err)
+		result.RecordAfterFailure(&chaosDetails, &resultDetails, failStep, clients, &eventsDetails)
+		os.Exit(1)
+	}
+
+	log.Info("[Revert]: Disk revert completed successfully")
+	resultDetails.Verdict = v1alpha1.ResultVerdictPassed
+
+	//Updating the chaosResult in the end of the revert
+	log.Infof("[The End]: Updating the chaos result of %v experiment (EOT)", experimentsDetails.ExperimentName)
+	if err = result.ChaosResult(&chaosDetails, clients, &resultDetails, "EOT"); err != nil {
+		log.Errorf("Unable to Update the Chaos Result, err: %v", err)
+		os.Exit(1)
+	}
+}
+
+//revertDisks reattaches the recorded disks which are still detached
+//it returns the fail step along with the error, if the revert fails
+func revertDisks(experimentsDetails *experimentTypes.ExperimentDetails, clients clients.ClientSets) (string, error) {
+
+	//DISPLAY THE TARGET INFORMATION
+	log.InfoWithValues("The target information is as follows", logrus.Fields{
+		"VM MOID":             experimentsDetails.AppVMMoids,
+		"VM Names":            experimentsDetails.AppVMNames,
+		"VM Tags":             experimentsDetails.VMTags,
+		"Recovery ConfigMaps": experimentsDetails.RecoveryConfigMaps,
+	})
+
+	// LOGIN TO VCENTER
+	vcenterClient, err := vcenter.Connect(context.Background(), &experimentsDetails.VcenterDetails, nil)
+	if err != nil {
+		return "[revert]: Unable to connect to the Vcenter server, err: " + err.Error(), err
+	}
+
+	// DELETE THE VCENTER SESSION ONCE THE REVERT COMPLETES
+	defer vcenter.Logout(vcenterClient)
+
+	//Resolve the target vms, the disks of all the vms are reverted if no target is provided
+	appVMMoidList, err := litmusLIB.ResolveTargetVMs(context.Background(), experimentsDetails, vcenterClient)
+	if err != nil {
+		return "[revert]: Failed to resolve the target vms, err: " + err.Error(), err
+	}
+
+	//Resolve the recovery configmaps containing the detached disk records
+	storeList, err := litmusLIB.ResolveRecoveryStores(experimentsDetails, clients)
+	if err != nil {
+		return "[revert]: Failed to resolve the recovery configmaps, err: " + err.Error(), err
+	}
+
+	if len(storeList) == 0 {
+		log.Infof("[Revert]: No recovery configmap found in %v namespace", experimentsDetails.ChaosNamespace)
+		return "", nil
+	}
+
+	//Reattach the recorded disks which are still detached
+	var report recovery.RevertReport
+	for _, store := range storeList {
+
+		log.Infof("[Revert]: Reattaching the disks recorded in %v configmap", store.Name())
+		storeReport, err := recovery.RevertDetachedDisks(context.Background(), appVMMoidList, store, vcenterClient, experimentsDetails.Delay, experimentsDetails.Timeout)
+		if err != nil {
+			return "[revert]: Failed to read the detached disk records, err: " + err.Error(), err
+		}
+
+		report.Reattached = append(report.Reattached, storeReport.Reattached...)
+		report.AlreadyAttached = append(report.AlreadyAttached, storeReport.AlreadyAttached...)
+		report.Failed = append(report.Failed, storeReport.Failed...)
+	}
+
+	//DISPLAY THE REVERT SUMMARY
+	log.InfoWithValues("[Revert]: The revert summary is as follows", logrus.Fields{
+		"Reattached Disks":       describeDisks(report.Reattached),
+		"Already Attached Disks": describeDisks(report.AlreadyAttached),
+		"Failed Disks":           describeDisks(report.Failed),
+	})
+
+	if len(report.Failed) != 0 {
+		err = errors.Errorf("failed to reattach %v disks (%v), their records are retained for the next revert", len(report.Failed), describeDisks(report.Failed))
+		return "[revert]: " + err.Error(), err
+	}
+
+	return "", nil
+}
+
+//describeDisks returns the disks in the vm:disk(path) form
+func describeDisks(diskList []recovery.DetachedDisk) string {
+	var descriptions []string
+	for _, disk := range diskList {
+		descriptions = append(descriptions, disk.VMMoid+":"+disk.DiskId+"("+disk.DiskPath+")")
+	}
+	return strings.Join(descriptions, ",")
+}
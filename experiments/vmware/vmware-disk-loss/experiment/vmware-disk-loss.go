@@ -1,20 +1,21 @@
 package experiment
 
 import (
+	"context"
+
 	litmusLIB "github.com/chaosnative/litmus-go/chaoslib/litmus/vmware-disk-loss/lib"
-	"github.com/chaosnative/litmus-go/pkg/cloud/vmware"
+	"github.com/chaosnative/litmus-go/pkg/vmware/abort"
+	"github.com/chaosnative/litmus-go/pkg/vmware/vcenter"
 	experimentEnv "github.com/chaosnative/litmus-go/pkg/vmware/vmware-disk-loss/environment"
 	experimentTypes "github.com/chaosnative/litmus-go/pkg/vmware/vmware-disk-loss/types"
 	"github.com/litmuschaos/chaos-operator/pkg/apis/litmuschaos/v1alpha1"
 	clients "github.com/litmuschaos/litmus-go/pkg/clients"
-	vmwareLib "github.com/litmuschaos/litmus-go/pkg/cloud/vmware"
 	"github.com/litmuschaos/litmus-go/pkg/events"
 	"github.com/litmuschaos/litmus-go/pkg/log"
 	"github.com/litmuschaos/litmus-go/pkg/probe"
 	"github.com/litmuschaos/litmus-go/pkg/result"
 	"github.com/litmuschaos/litmus-go/pkg/status"
 	"github.com/litmuschaos/litmus-go/pkg/types"
-	"github.com/litmuschaos/litmus-go/pkg/utils/common"
 	"github.com/sirupsen/logrus"
 )
 
@@ -61,8 +62,9 @@ func VMWareDiskLoss(clients clients.ClientSets) {
 	types.SetResultEventAttributes(&eventsDetails, types.AwaitedVerdict, msg, "Normal", &resultDetails)
 	events.GenerateEvents(&eventsDetails, clients, &chaosDetails, "ChaosResult")
 
-	// Calling AbortWatcher go routine, it will continuously watch for the abort signal and generate the required events and result
-	go common.AbortWatcher(experimentsDetails.ExperimentName, clients, &resultDetails, &chaosDetails, &eventsDetails)
+	// Calling the abort watcher, it will continuously watch for the abort signal, revert the chaos, generate the required events and result and release the vcenter session
+	abortWatcher := abort.NewWatcher(experimentsDetails.ExperimentName, clients, &resultDetails, &chaosDetails, &eventsDetails)
+	abortWatcher.Start()
 
 	//DISPLAY THE APP INFORMATION
 	log.InfoWithValues("[Info]: The application information is as follows", logrus.Fields{
@@ -73,19 +75,46 @@ func VMWareDiskLoss(clients clients.ClientSets) {
 
 	//DISPLAY THE DISK INFORMATION
 	log.InfoWithValues("The disk information is as follows", logrus.Fields{
-		"Disk IDs": experimentsDetails.DiskIds,
-		"VM MOID":  experimentsDetails.AppVMMoids,
+		"Disk IDs":       experimentsDetails.DiskIds,
+		"Disk Selectors": experimentsDetails.DiskSelectors,
+		"VM MOID":        experimentsDetails.AppVMMoids,
+		"VM Names":       experimentsDetails.AppVMNames,
+		"VM Tags":        experimentsDetails.VMTags,
+		"Dry Run":        experimentsDetails.DryRun,
 	})
 
-	// GET SESSION ID TO LOGIN TO VCENTER
-	cookie, err := vmwareLib.GetVcenterSessionID(experimentsDetails.VcenterServer, experimentsDetails.VcenterUser, experimentsDetails.VcenterPass)
+	// LOGIN TO VCENTER, THE SESSION IS RELEASED BY THE ABORT WATCHER ON ABORT
+	vcenterClient, err := vcenter.Connect(context.Background(), &experimentsDetails.VcenterDetails, abortWatcher)
 	if err != nil {
-		failStep := "[pre-chaos]: Unable to get Vcenter session ID, err: " + err.Error()
+		failStep := "[pre-chaos]: Unable to connect to the Vcenter server, err: " + err.Error()
 		result.RecordAfterFailure(&chaosDetails, &resultDetails, failStep, clients, &eventsDetails)
 		log.Errorf("Vcenter Login failed, err: %v", err)
 		return
 	}
 
+	// DELETE THE VCENTER SESSION ONCE THE EXPERIMENT COMPLETES
+	defer vcenter.Logout(vcenterClient)
+
+	//Reattach the disks left detached by a previous run of the experiment
+	//the dry run only lists them in the chaos plan, without changing the inventory
+	store := litmusLIB.NewRecoveryStore(&experimentsDetails, clients)
+	if !experimentsDetails.DryRun {
+		if err = litmusLIB.RecoverDetachedDisks(context.Background(), &experimentsDetails, store, vcenterClient); err != nil {
+			log.Errorf("Disk recovery failed, err: %v", err)
+			failStep := "[pre-chaos]: Failed to reattach the disks detached by a previous run, err: " + err.Error()
+			result.RecordAfterFailure(&chaosDetails, &resultDetails, failStep, clients, &eventsDetails)
+			return
+		}
+	}
+
+	//Resolve the target vms and disks from the vm names, disk selectors or vm tags
+	if err = litmusLIB.ResolveTargets(context.Background(), &experimentsDetails, vcenterClient); err != nil {
+		log.Errorf("Target resolution failed, err: %v", err)
+		failStep := "[pre-chaos]: Failed to resolve the target disks, err: " + err.Error()
+		result.RecordAfterFailure(&chaosDetails, &resultDetails, failStep, clients, &eventsDetails)
+		return
+	}
+
 	//PRE-CHAOS APPLICATION STATUS CHECK
 	log.Info("[Status]: Verify that the AUT (Application Under Test) is running (pre-chaos)")
 	if err = status.AUTStatusCheck(experimentsDetails.AppNS, experimentsDetails.AppLabel, experimentsDetails.TargetContainer, experimentsDetails.Timeout, experimentsDetails.Delay, clients, &chaosDetails); err != nil {
@@ -129,18 +158,59 @@ func VMWareDiskLoss(clients clients.ClientSets) {
 		events.GenerateEvents(&eventsDetails, clients, &chaosDetails, "ChaosEngine")
 	}
 
+	//Print the detach/attach plan and skip the chaos injection in dry run mode
+	//the disk state and boot disk checks are reported as warnings after the plan, so that the complete plan is printed
+	if experimentsDetails.DryRun {
+		if err = litmusLIB.PrintDiskLossPlan(context.Background(), &experimentsDetails, store, vcenterClient); err != nil {
+			log.Errorf("Dry run failed, err: %v", err)
+			failStep := "[pre-chaos]: Failed to prepare the chaos plan, err: " + err.Error()
+			result.RecordAfterFailure(&chaosDetails, &resultDetails, failStep, clients, &eventsDetails)
+			return
+		}
+
+		if err := vcenterClient.DiskStateCheck(context.Background(), experimentsDetails.AppVMMoids, experimentsDetails.DiskIds); err != nil {
+			log.Warnf("[Dry Run]: The disk status check fails the actual run, err: %v", err)
+		}
+
+		if experimentsDetails.AllowBootDetach {
+			log.Warn("[Dry Run]: Boot disk detachment is allowed, the target vms may go down in the actual run")
+		} else if err := vcenterClient.BootDiskCheck(context.Background(), experimentsDetails.AppVMMoids, experimentsDetails.DiskIds); err != nil {
+			log.Warnf("[Dry Run]: The boot disk check fails the actual run, err: %v", err)
+		}
+
+		log.Infof("[Dry Run]: Pre-chaos checks completed, skipping the %v chaos injection", experimentsDetails.ExperimentName)
+		resultDetails.Verdict = v1alpha1.ResultVerdictPassed
+
+		//Updating the chaosResult in the end of experiment
+		log.Infof("[The End]: Updating the chaos result of %v experiment (EOT)", experimentsDetails.ExperimentName)
+		if err := result.ChaosResult(&chaosDetails, clients, &resultDetails, "EOT"); err != nil {
+			log.Errorf("Unable to Update the Chaos Result, err: %v", err)
+		}
+		return
+	}
+
 	//Verify the disk is attached to the specified vm
-	if err := vmware.DiskStateCheck(experimentsDetails.VcenterServer, experimentsDetails.AppVMMoids, experimentsDetails.DiskIds, cookie); err != nil {
+	if err := vcenterClient.DiskStateCheck(context.Background(), experimentsDetails.AppVMMoids, experimentsDetails.DiskIds); err != nil {
 		log.Errorf("disk status check failed pre chaos, err: %v", err)
 		failStep := "[pre-chaos]: Failed to verify that the disk is attached to vm, err: " + err.Error()
 		result.RecordAfterFailure(&chaosDetails, &resultDetails, failStep, clients, &eventsDetails)
 		return
 	}
 
+	//Verify that none of the target disks is a boot disk
+	if experimentsDetails.AllowBootDetach {
+		log.Warn("[Warning]: Boot disk detachment is allowed, the target vms may go down")
+	} else if err := vcenterClient.BootDiskCheck(context.Background(), experimentsDetails.AppVMMoids, experimentsDetails.DiskIds); err != nil {
+		log.Errorf("boot disk check failed pre chaos, err: %v", err)
+		failStep := "[pre-chaos]: Failed to verify that the target disks are not boot disks, err: " + err.Error()
+		result.RecordAfterFailure(&chaosDetails, &resultDetails, failStep, clients, &eventsDetails)
+		return
+	}
+
 	// Including the litmus lib for disk-loss
 	switch experimentsDetails.ChaosLib {
 	case "litmus":
-		if err = litmusLIB.PrepareDiskLoss(&experimentsDetails, clients, &resultDetails, &eventsDetails, &chaosDetails, cookie); err != nil {
+		if err = litmusLIB.PrepareDiskLoss(&experimentsDetails, clients, &resultDetails, &eventsDetails, &chaosDetails, vcenterClient, abortWatcher); err != nil {
 			log.Errorf("Chaos injection failed, err: %v", err)
 			failStep := "[chaos]: Failed inside the chaoslib, err: " + err.Error()
 			result.RecordAfterFailure(&chaosDetails, &resultDetails, failStep, clients, &eventsDetails)
@@ -0,0 +1,13 @@
+package experiment
+
+import (
+	"github.com/chaosnative/litmus-go/pkg/cloud/vmware"
+	"github.com/chaosnative/litmus-go/pkg/vmware/vmware-device-chaos/runner"
+	clients "github.com/litmuschaos/litmus-go/pkg/clients"
+)
+
+// VMWareNICDisconnect contains steps to inject chaos
+// it disconnects the virtual ethernet adapters of the vms using the device chaos lib
+func VMWareNICDisconnect(clients clients.ClientSets) {
+	runner.DeviceChaos(clients, "vmware-nic-disconnect", vmware.DeviceTypeEthernet)
+}
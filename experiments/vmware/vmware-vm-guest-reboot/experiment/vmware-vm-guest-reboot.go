@@ -0,0 +1,13 @@
+package experiment
+
+import (
+	litmusLIB "github.com/chaosnative/litmus-go/chaoslib/litmus/vmware-vm-power/lib"
+	"github.com/chaosnative/litmus-go/pkg/vmware/vmware-vm-power/runner"
+	clients "github.com/litmuschaos/litmus-go/pkg/clients"
+)
+
+// VMWareVMGuestReboot contains steps to inject chaos
+// it reboots or shuts down the guest os of the vms, as per the GUEST_POWER_ACTION env, using the vm power chaos lib
+func VMWareVMGuestReboot(clients clients.ClientSets) {
+	runner.VMPowerChaos(clients, "vmware-vm-guest-reboot", litmusLIB.PowerActionGuest)
+}
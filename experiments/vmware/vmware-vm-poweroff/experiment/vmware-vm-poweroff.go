@@ -0,0 +1,13 @@
+package experiment
+
+import (
+	litmusLIB "github.com/chaosnative/litmus-go/chaoslib/litmus/vmware-vm-power/lib"
+	"github.com/chaosnative/litmus-go/pkg/vmware/vmware-vm-power/runner"
+	clients "github.com/litmuschaos/litmus-go/pkg/clients"
+)
+
+// VMWareVMPowerOff contains steps to inject chaos
+// it powers off and powers on the vms using the vm power chaos lib
+func VMWareVMPowerOff(clients clients.ClientSets) {
+	runner.VMPowerChaos(clients, "vmware-vm-poweroff", litmusLIB.PowerActionPowerOff)
+}
@@ -0,0 +1,13 @@
+package experiment
+
+import (
+	litmusLIB "github.com/chaosnative/litmus-go/chaoslib/litmus/vmware-vm-power/lib"
+	"github.com/chaosnative/litmus-go/pkg/vmware/vmware-vm-power/runner"
+	clients "github.com/litmuschaos/litmus-go/pkg/clients"
+)
+
+// VMWareVMSuspend contains steps to inject chaos
+// it suspends and resumes the vms using the vm power chaos lib
+func VMWareVMSuspend(clients clients.ClientSets) {
+	runner.VMPowerChaos(clients, "vmware-vm-suspend", litmusLIB.PowerActionSuspend)
+}
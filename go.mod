@@ -7,7 +7,10 @@ require (
 	github.com/litmuschaos/litmus-go v0.0.0-20211019165030-0f750768d529
 	github.com/pkg/errors v0.9.1
 	github.com/sirupsen/logrus v1.7.0
+	github.com/vmware/govmomi v0.26.1
+	k8s.io/api v0.17.3
 	k8s.io/apimachinery v0.17.3
+	k8s.io/client-go v12.0.0+incompatible
 )
 
 // Pinned to kubernetes-1.16.2
@@ -0,0 +1,118 @@
+package vmware
+
+import (
+	"encoding/json"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+// APIFlavour is the flavour of the vsphere automation api used by the client
+type APIFlavour string
+
+const (
+	// APIFlavourRest is the deprecated /rest api, with {"value": ...} wrapped payloads and cookie based auth
+	APIFlavourRest APIFlavour = "rest"
+	// APIFlavourAPI is the /api api (vsphere 7.0U2+), with unwrapped payloads and header based auth
+	APIFlavourAPI APIFlavour = "api"
+	// APIFlavourAuto detects the api flavour from the vcenter server during login
+	APIFlavourAuto APIFlavour = "auto"
+)
+
+// ParseAPIFlavour converts the given value into an APIFlavour
+func ParseAPIFlavour(flavour string) (APIFlavour, error) {
+	switch APIFlavour(strings.ToLower(strings.TrimSpace(flavour))) {
+	case APIFlavourRest:
+		return APIFlavourRest, nil
+	case APIFlavourAPI:
+		return APIFlavourAPI, nil
+	case APIFlavourAuto, "":
+		return APIFlavourAuto, nil
+	default:
+		return "", errors.Errorf("%v api flavour is not supported, supported values are rest, api and auto", flavour)
+	}
+}
+
+// sessionPath returns the session api path for the given flavour
+func (flavour APIFlavour) sessionPath() string {
+	if flavour == APIFlavourAPI {
+		return "/api/session"
+	}
+	return "/rest/com/vmware/cis/session"
+}
+
+// resourcePath returns the api path of the given resource (e.g, /vcenter/vm) for the given flavour
+func (flavour APIFlavour) resourcePath(path string) string {
+	if flavour == APIFlavourAPI {
+		return "/api" + path
+	}
+	return "/rest" + path
+}
+
+// taggingPath returns the tagging api path of the given collection (e.g, /tag) and action for the given flavour
+// the tagging api lives under /com/vmware/cis for the /rest api and under /cis for the /api api
+func (flavour APIFlavour) taggingPath(collection, id, action string) string {
+
+	path := "/cis/tagging" + collection
+	if flavour != APIFlavourAPI {
+		path = "/com/vmware" + path
+	}
+
+	if id != "" {
+		if flavour == APIFlavourAPI {
+			path = path + "/" + id
+		} else {
+			path = path + "/id:" + id
+		}
+	}
+
+	if action != "" {
+		if flavour == APIFlavourAPI {
+			path = path + "?action=" + action
+		} else {
+			path = path + "?~action=" + action
+		}
+	}
+
+	return path
+}
+
+// actionPath returns the path of the given action (e.g, stop) on the given resource (e.g, /vcenter/vm/vm-1/power) for the given flavour
+// the /rest api expects the action as a path segment, whereas the /api api expects it as a query parameter
+func (flavour APIFlavour) actionPath(path, action string) string {
+	if flavour == APIFlavourAPI {
+		return path + "?action=" + action
+	}
+	return path + "/" + action
+}
+
+// filterParam returns the query parameter name of the given list filter (e.g, names) for the given flavour
+// the /rest api expects the filters to be prefixed with filter.
+func (flavour APIFlavour) filterParam(filter string) string {
+	if flavour == APIFlavourAPI {
+		return filter
+	}
+	return "filter." + filter
+}
+
+// encodeSpec encodes the given spec as request payload for the given flavour
+// the /rest api expects the spec to be wrapped inside {"spec": ...}
+func (flavour APIFlavour) encodeSpec(spec interface{}) ([]byte, error) {
+	if flavour == APIFlavourAPI {
+		return json.Marshal(spec)
+	}
+	return json.Marshal(struct {
+		Spec interface{} `json:"spec"`
+	}{Spec: spec})
+}
+
+// decodeValue decodes the response body into the given value for the given flavour
+// the /rest api wraps the response inside {"value": ...}
+func (flavour APIFlavour) decodeValue(body []byte, value interface{}) error {
+	if flavour == APIFlavourAPI {
+		return json.Unmarshal(body, value)
+	}
+	return json.Unmarshal(body, &struct {
+		Value interface{} `json:"value"`
+	}{Value: value})
+}
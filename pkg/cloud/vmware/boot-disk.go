@@ -0,0 +1,145 @@
+package vmware
+
+import (
+	"context"
+	"sort"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+// controllerBootPriority is the order in which the controllers are probed for the boot disk,
+// when no disk is present in the boot order of the vm
+var controllerBootPriority = map[string]int{
+	"SCSI": 0,
+	"SATA": 1,
+	"NVME": 2,
+	"IDE":  3,
+}
+
+// GetBootDiskIds returns the ids of the boot disks of the given vm
+// the disks present in the boot order are considered as boot disks, if the boot order
+// does not contain any disk, the first disk on the boot controller is considered as the boot disk
+func (c *VcenterClient) GetBootDiskIds(ctx context.Context, appVMMoid string) ([]string, error) {
+
+	diskList, err := c.ListDisks(ctx, appVMMoid)
+	if err != nil {
+		return nil, err
+	}
+
+	return c.getBootDiskIds(ctx, appVMMoid, diskList)
+}
+
+// GetNonBootDisks returns the details of the disks of the given vm, which are not boot disks
+func (c *VcenterClient) GetNonBootDisks(ctx context.Context, appVMMoid string) ([]DiskInfo, error) {
+
+	diskList, err := c.ListDisks(ctx, appVMMoid)
+	if err != nil {
+		return nil, err
+	}
+
+	bootDiskIds, err := c.getBootDiskIds(ctx, appVMMoid, diskList)
+	if err != nil {
+		return nil, err
+	}
+
+	var nonBootDisks []DiskInfo
+	for _, disk := range diskList {
+		if !containsString(bootDiskIds, disk.Disk) {
+			nonBootDisks = append(nonBootDisks, disk)
+		}
+	}
+
+	return nonBootDisks, nil
+}
+
+// getBootDiskIds returns the ids of the boot disks out of the given disks of the vm
+func (c *VcenterClient) getBootDiskIds(ctx context.Context, appVMMoid string, diskList []DiskInfo) ([]string, error) {
+
+	type BootDevice struct {
+		MsgType  string   `json:"type"`
+		MsgDisks []string `json:"disks"`
+	}
+
+	body, err := c.do(ctx, "GET", "/vcenter/vm/"+appVMMoid+"/hardware/boot/device", nil, "boot order fetch")
+	if err != nil {
+		return nil, err
+	}
+
+	var bootDevices []BootDevice
+	if err = c.decodeValue(body, &bootDevices); err != nil {
+		return nil, err
+	}
+
+	var bootDiskIds []string
+	for _, bootDevice := range bootDevices {
+		if strings.EqualFold(bootDevice.MsgType, "DISK") {
+			bootDiskIds = append(bootDiskIds, bootDevice.MsgDisks...)
+		}
+	}
+
+	if len(bootDiskIds) != 0 || len(diskList) == 0 {
+		return bootDiskIds, nil
+	}
+
+	// no disk is present in the boot order, the firmware boots from the first disk on the boot controller
+	sortedDisks := append([]DiskInfo{}, diskList...)
+	sort.SliceStable(sortedDisks, func(i, j int) bool {
+		if sortedDisks[i].Type != sortedDisks[j].Type {
+			return controllerPriority(sortedDisks[i].Type) < controllerPriority(sortedDisks[j].Type)
+		}
+		if sortedDisks[i].Bus != sortedDisks[j].Bus {
+			return sortedDisks[i].Bus < sortedDisks[j].Bus
+		}
+		return sortedDisks[i].Unit < sortedDisks[j].Unit
+	})
+
+	return []string{sortedDisks[0].Disk}, nil
+}
+
+// controllerPriority returns the boot priority of the given controller type
+func controllerPriority(controllerType string) int {
+	if priority, ok := controllerBootPriority[strings.ToUpper(controllerType)]; ok {
+		return priority
+	}
+	return len(controllerBootPriority)
+}
+
+// containsString checks whether the given list contains the given value
+func containsString(list []string, value string) bool {
+	for _, item := range list {
+		if item == value {
+			return true
+		}
+	}
+	return false
+}
+
+// BootDiskCheck verifies that none of the given disks is a boot disk of the corresponding vm
+func (c *VcenterClient) BootDiskCheck(ctx context.Context, appVMMoids, diskIds string) error {
+
+	diskIdList := strings.Split(diskIds, ",")
+	appVMMoidList := strings.Split(appVMMoids, ",")
+
+	if len(diskIdList) != len(appVMMoidList) {
+		return errors.Errorf("unequal number of disk ids and vm ids found, please verify the input details")
+	}
+
+	bootDiskIds := map[string][]string{}
+	for i := range diskIdList {
+
+		if _, ok := bootDiskIds[appVMMoidList[i]]; !ok {
+			vmBootDiskIds, err := c.GetBootDiskIds(ctx, appVMMoidList[i])
+			if err != nil {
+				return errors.Errorf("failed to get the boot disks of %v vm, err: %v", appVMMoidList[i], err)
+			}
+			bootDiskIds[appVMMoidList[i]] = vmBootDiskIds
+		}
+
+		if containsString(bootDiskIds[appVMMoidList[i]], diskIdList[i]) {
+			return errors.Errorf("%v disk is the boot disk of %v vm, set ALLOW_BOOT_DISK_DETACH to true to detach it", diskIdList[i], appVMMoidList[i])
+		}
+	}
+
+	return nil
+}
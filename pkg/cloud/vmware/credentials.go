@@ -0,0 +1,66 @@
+package vmware
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+var (
+	// userFileNames are the file names checked for the vcenter user inside the credentials directory
+	userFileNames = []string{"VCENTERUSER", "username"}
+	// passFileNames are the file names checked for the vcenter password inside the credentials directory
+	passFileNames = []string{"VCENTERPASS", "password"}
+)
+
+// CredentialsProvider returns the credentials used to login to the vcenter server
+// it is invoked for every login, so that the rotated credentials are picked up
+type CredentialsProvider func() (user, pass string, err error)
+
+// StaticCredentials returns a CredentialsProvider for the given credentials
+func StaticCredentials(user, pass string) CredentialsProvider {
+	return func() (string, string, error) {
+		return user, pass, nil
+	}
+}
+
+// FileCredentials returns a CredentialsProvider which reads the credentials from the given directory
+// the directory is generally a mounted secret, containing the VCENTERUSER and VCENTERPASS (or username and password) keys
+func FileCredentials(credentialsPath string) CredentialsProvider {
+	return func() (string, string, error) {
+
+		user, err := readCredentialFile(credentialsPath, userFileNames)
+		if err != nil {
+			return "", "", err
+		}
+
+		pass, err := readCredentialFile(credentialsPath, passFileNames)
+		if err != nil {
+			return "", "", err
+		}
+
+		return user, pass, nil
+	}
+}
+
+// readCredentialFile reads the first available file out of the given file names from the credentials directory
+func readCredentialFile(credentialsPath string, fileNames []string) (string, error) {
+
+	for _, fileName := range fileNames {
+
+		value, err := ioutil.ReadFile(filepath.Join(credentialsPath, fileName))
+		if err != nil {
+			if os.IsNotExist(err) {
+				continue
+			}
+			return "", errors.Errorf("unable to read %v credential file from %v, err: %v", fileName, credentialsPath, err)
+		}
+
+		return strings.TrimSpace(string(value)), nil
+	}
+
+	return "", errors.Errorf("none of the %v credential files found in %v", strings.Join(fileNames, ", "), credentialsPath)
+}
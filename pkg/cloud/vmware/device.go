@@ -0,0 +1,205 @@
+package vmware
+
+import (
+	"context"
+	"strings"
+
+	"github.com/litmuschaos/litmus-go/pkg/log"
+	"github.com/pkg/errors"
+	"github.com/sirupsen/logrus"
+)
+
+const (
+	// DeviceTypeCdrom is the type of the virtual cd-rom devices
+	DeviceTypeCdrom = "cdrom"
+	// DeviceTypeSerial is the type of the virtual serial ports
+	DeviceTypeSerial = "serial"
+	// DeviceTypeFloppy is the type of the virtual floppy drives
+	DeviceTypeFloppy = "floppy"
+	// DeviceTypeEthernet is the type of the virtual ethernet adapters
+	DeviceTypeEthernet = "ethernet"
+	// DeviceTypeDisk is the type of the virtual disks, which are detached and reattached instead of being disconnected
+	DeviceTypeDisk = "disk"
+
+	// DeviceStateConnected is the state of a connected virtual device
+	DeviceStateConnected = "CONNECTED"
+	// DeviceStateNotConnected is the state of a disconnected virtual device
+	DeviceStateNotConnected = "NOT_CONNECTED"
+)
+
+// deviceIdKeys contains the key of the device id in the device list response of the connectable device types
+var deviceIdKeys = map[string]string{
+	DeviceTypeCdrom:    "cdrom",
+	DeviceTypeSerial:   "port",
+	DeviceTypeFloppy:   "floppy",
+	DeviceTypeEthernet: "nic",
+}
+
+// IsConnectableDevice returns true if the devices of the given type can be connected and disconnected
+func IsConnectableDevice(deviceType string) bool {
+	_, ok := deviceIdKeys[deviceType]
+	return ok
+}
+
+// DeviceInfo contains the details of a connectable virtual device
+type DeviceInfo struct {
+	Device string
+	Label  string
+	State  string
+}
+
+// GetDeviceInfo returns the details of the given connectable virtual device
+func (c *VcenterClient) GetDeviceInfo(ctx context.Context, appVMMoid, deviceType, deviceId string) (DeviceInfo, error) {
+
+	type DeviceDetails struct {
+		MsgLabel string `json:"label"`
+		MsgState string `json:"state"`
+	}
+
+	body, err := c.do(ctx, "GET", "/vcenter/vm/"+appVMMoid+"/hardware/"+deviceType+"/"+deviceId, nil, deviceType+" information fetch")
+	if err != nil {
+		return DeviceInfo{}, err
+	}
+
+	var deviceDetails DeviceDetails
+	if err = c.decodeValue(body, &deviceDetails); err != nil {
+		return DeviceInfo{}, err
+	}
+
+	return DeviceInfo{
+		Device: deviceId,
+		Label:  deviceDetails.MsgLabel,
+		State:  deviceDetails.MsgState,
+	}, nil
+}
+
+// ListDevices returns the details of all the connectable virtual devices of the given type of the vm
+func (c *VcenterClient) ListDevices(ctx context.Context, appVMMoid, deviceType string) ([]DeviceInfo, error) {
+
+	idKey, ok := deviceIdKeys[deviceType]
+	if !ok {
+		return nil, errors.Errorf("%v device type is not connectable", deviceType)
+	}
+
+	body, err := c.do(ctx, "GET", "/vcenter/vm/"+appVMMoid+"/hardware/"+deviceType, nil, deviceType+" list fetch")
+	if err != nil {
+		return nil, err
+	}
+
+	var deviceSummaryList []map[string]interface{}
+	if err = c.decodeValue(body, &deviceSummaryList); err != nil {
+		return nil, err
+	}
+
+	var deviceList []DeviceInfo
+	for _, deviceSummary := range deviceSummaryList {
+
+		deviceId, ok := deviceSummary[idKey].(string)
+		if !ok {
+			return nil, errors.Errorf("failed to get the id of the %v device", deviceType)
+		}
+
+		deviceInfo, err := c.GetDeviceInfo(ctx, appVMMoid, deviceType, deviceId)
+		if err != nil {
+			return nil, err
+		}
+
+		deviceList = append(deviceList, deviceInfo)
+	}
+
+	return deviceList, nil
+}
+
+// ResolveDeviceSelector returns the id of the connectable virtual device matching the given selector
+// the selector can be the device id (3000) or the device label (CD/DVD drive 1)
+func (c *VcenterClient) ResolveDeviceSelector(ctx context.Context, appVMMoid, deviceType, deviceSelector string) (string, error) {
+
+	if deviceSelector == "" {
+		return "", errors.Errorf("no %v selector provided for %v vm", deviceType, appVMMoid)
+	}
+
+	deviceList, err := c.ListDevices(ctx, appVMMoid, deviceType)
+	if err != nil {
+		return "", err
+	}
+
+	for _, device := range deviceList {
+		if device.Device == deviceSelector || strings.EqualFold(device.Label, deviceSelector) {
+
+			log.InfoWithValues("[Info]: The device selector is resolved as follows", logrus.Fields{
+				"VM ID":           appVMMoid,
+				"Device Type":     deviceType,
+				"Device Selector": deviceSelector,
+				"Device ID":       device.Device,
+				"Device Label":    device.Label,
+			})
+
+			return device.Device, nil
+		}
+	}
+
+	return "", errors.Errorf("no %v device found matching %v selector in %v vm", deviceType, deviceSelector, appVMMoid)
+}
+
+// DeviceDisconnect will disconnect a connectable virtual device of a VM
+func (c *VcenterClient) DeviceDisconnect(ctx context.Context, appVMMoid, deviceType, deviceId string) error {
+
+	if _, err := c.do(ctx, "POST", c.APIFlavour().actionPath("/vcenter/vm/"+appVMMoid+"/hardware/"+deviceType+"/"+deviceId, "disconnect"), nil, deviceType+" disconnection"); err != nil {
+		return err
+	}
+
+	log.InfoWithValues("Disconnected device having:", logrus.Fields{
+		"VM ID":       appVMMoid,
+		"Device Type": deviceType,
+		"Device ID":   deviceId,
+	})
+
+	return nil
+}
+
+// DeviceConnect will connect a connectable virtual device of a VM
+func (c *VcenterClient) DeviceConnect(ctx context.Context, appVMMoid, deviceType, deviceId string) error {
+
+	if _, err := c.do(ctx, "POST", c.APIFlavour().actionPath("/vcenter/vm/"+appVMMoid+"/hardware/"+deviceType+"/"+deviceId, "connect"), nil, deviceType+" connection"); err != nil {
+		return err
+	}
+
+	log.InfoWithValues("Connected device having:", logrus.Fields{
+		"VM ID":       appVMMoid,
+		"Device Type": deviceType,
+		"Device ID":   deviceId,
+	})
+
+	return nil
+}
+
+// GetDeviceState returns the connection state of the given connectable virtual device, e.g, CONNECTED
+func (c *VcenterClient) GetDeviceState(ctx context.Context, appVMMoid, deviceType, deviceId string) (string, error) {
+
+	deviceInfo, err := c.GetDeviceInfo(ctx, appVMMoid, deviceType, deviceId)
+	if err != nil {
+		return "", err
+	}
+
+	return deviceInfo.State, nil
+}
+
+// WaitForDeviceState waits for the given connectable virtual device to get in the given connection state
+func (c *VcenterClient) WaitForDeviceState(ctx context.Context, appVMMoid, deviceType, deviceId, deviceState string, delay, timeout int) error {
+
+	log.Infof("[Status]: Checking %v %v device for %v state", deviceId, deviceType, deviceState)
+	return PollUntil(ctx, delay, timeout, func(attempt uint) error {
+
+		state, err := c.GetDeviceState(ctx, appVMMoid, deviceType, deviceId)
+		if err != nil {
+			return errors.Errorf("failed to get the %v device state, err: %v", deviceType, err)
+		}
+
+		log.Infof("[Info]: The %v device state is %v", deviceType, state)
+		if state != deviceState {
+			return errors.Errorf("%v device is not yet in %v state", deviceType, deviceState)
+		}
+
+		return nil
+	})
+}
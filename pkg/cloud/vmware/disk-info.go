@@ -0,0 +1,230 @@
+package vmware
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/litmuschaos/litmus-go/pkg/log"
+	"github.com/pkg/errors"
+	"github.com/sirupsen/logrus"
+)
+
+// diskPositionRegex matches the disk position selector, e.g, scsi:0:1 or 0:1
+var diskPositionRegex = regexp.MustCompile(`^(?i)(?:(scsi|sata|nvme|ide):)?(\d+):(\d+)$`)
+
+// DiskInfo contains the details of a virtual disk
+type DiskInfo struct {
+	Disk     string
+	Label    string
+	Type     string
+	Bus      int
+	Unit     int
+	VMDKFile string
+}
+
+// Position returns the controller type and the bus:unit position of the disk, e.g, SCSI(0:1)
+func (disk DiskInfo) Position() string {
+	return fmt.Sprintf("%s(%d:%d)", disk.Type, disk.Bus, disk.Unit)
+}
+
+// GetDiskInfo returns the details of the given disk
+func (c *VcenterClient) GetDiskInfo(ctx context.Context, appVMMoid, diskId string) (DiskInfo, error) {
+
+	type DiskAddress struct {
+		MsgBus  int `json:"bus"`
+		MsgUnit int `json:"unit"`
+	}
+
+	type DiskDetails struct {
+		MsgLabel string       `json:"label"`
+		MsgType  string       `json:"type"`
+		MsgSCSI  *DiskAddress `json:"scsi"`
+		MsgSATA  *DiskAddress `json:"sata"`
+		MsgNVME  *DiskAddress `json:"nvme"`
+		MsgIDE   *struct {
+			MsgPrimary bool `json:"primary"`
+			MsgMaster  bool `json:"master"`
+		} `json:"ide"`
+		MsgBacking struct {
+			MsgVMDKFile string `json:"vmdk_file"`
+		} `json:"backing"`
+	}
+
+	body, err := c.do(ctx, "GET", "/vcenter/vm/"+appVMMoid+"/hardware/disk/"+diskId, nil, "disk information fetch")
+	if err != nil {
+		return DiskInfo{}, err
+	}
+
+	var diskDetails DiskDetails
+	if err = c.decodeValue(body, &diskDetails); err != nil {
+		return DiskInfo{}, err
+	}
+
+	diskInfo := DiskInfo{
+		Disk:     diskId,
+		Label:    diskDetails.MsgLabel,
+		Type:     diskDetails.MsgType,
+		VMDKFile: diskDetails.MsgBacking.MsgVMDKFile,
+	}
+
+	var address *DiskAddress
+	switch {
+	case diskDetails.MsgSCSI != nil:
+		address = diskDetails.MsgSCSI
+	case diskDetails.MsgSATA != nil:
+		address = diskDetails.MsgSATA
+	case diskDetails.MsgNVME != nil:
+		address = diskDetails.MsgNVME
+	case diskDetails.MsgIDE != nil:
+		// ide disks are addressed as primary/secondary channel and master/slave device
+		address = &DiskAddress{}
+		if !diskDetails.MsgIDE.MsgPrimary {
+			address.MsgBus = 1
+		}
+		if !diskDetails.MsgIDE.MsgMaster {
+			address.MsgUnit = 1
+		}
+	}
+
+	if address != nil {
+		diskInfo.Bus = address.MsgBus
+		diskInfo.Unit = address.MsgUnit
+	}
+
+	return diskInfo, nil
+}
+
+// ListDisks returns the details of all the disks attached to the given vm
+func (c *VcenterClient) ListDisks(ctx context.Context, appVMMoid string) ([]DiskInfo, error) {
+
+	type DiskSummary struct {
+		MsgDisk string `json:"disk"`
+	}
+
+	body, err := c.do(ctx, "GET", "/vcenter/vm/"+appVMMoid+"/hardware/disk", nil, "disk list fetch")
+	if err != nil {
+		return nil, err
+	}
+
+	var diskSummaryList []DiskSummary
+	if err = c.decodeValue(body, &diskSummaryList); err != nil {
+		return nil, err
+	}
+
+	var diskList []DiskInfo
+	for _, diskSummary := range diskSummaryList {
+
+		diskInfo, err := c.GetDiskInfo(ctx, appVMMoid, diskSummary.MsgDisk)
+		if err != nil {
+			return nil, err
+		}
+
+		diskList = append(diskList, diskInfo)
+	}
+
+	return diskList, nil
+}
+
+// GetDiskIds resolves the given comma separated disk selectors into comma separated disk ids
+// the selectors are resolved against the corresponding comma separated vm moids
+func (c *VcenterClient) GetDiskIds(ctx context.Context, appVMMoids, diskSelectors string) (string, error) {
+
+	appVMMoidList := strings.Split(appVMMoids, ",")
+	diskSelectorList := strings.Split(diskSelectors, ",")
+
+	if len(appVMMoidList) != len(diskSelectorList) {
+		return "", errors.Errorf("unequal number of disk selectors and vm ids found, please verify the input details")
+	}
+
+	var diskIdList []string
+	for i := range diskSelectorList {
+
+		diskId, err := c.ResolveDiskSelector(ctx, strings.TrimSpace(appVMMoidList[i]), strings.TrimSpace(diskSelectorList[i]))
+		if err != nil {
+			return "", err
+		}
+
+		diskIdList = append(diskIdList, diskId)
+	}
+
+	return strings.Join(diskIdList, ","), nil
+}
+
+// ResolveDiskSelector returns the id of the disk matching the given selector
+// the selector can be the disk id (2001), the disk label (Hard disk 2),
+// the disk position (scsi:0:1 or 0:1) or the vmdk file path ([datastore1] app/app_1.vmdk)
+func (c *VcenterClient) ResolveDiskSelector(ctx context.Context, appVMMoid, diskSelector string) (string, error) {
+
+	if diskSelector == "" {
+		return "", errors.Errorf("no disk selector provided for %v vm", appVMMoid)
+	}
+
+	diskList, err := c.ListDisks(ctx, appVMMoid)
+	if err != nil {
+		return "", err
+	}
+
+	var match func(disk DiskInfo) bool
+
+	switch {
+	case strings.HasPrefix(diskSelector, "["):
+		match = func(disk DiskInfo) bool {
+			return disk.VMDKFile == diskSelector
+		}
+	case diskPositionRegex.MatchString(diskSelector):
+		position := diskPositionRegex.FindStringSubmatch(diskSelector)
+		bus, _ := strconv.Atoi(position[2])
+		unit, _ := strconv.Atoi(position[3])
+		match = func(disk DiskInfo) bool {
+			return (position[1] == "" || strings.EqualFold(disk.Type, position[1])) && disk.Bus == bus && disk.Unit == unit
+		}
+	default:
+		match = func(disk DiskInfo) bool {
+			return disk.Disk == diskSelector || strings.EqualFold(disk.Label, diskSelector)
+		}
+	}
+
+	var matchedDisks []DiskInfo
+	for _, disk := range diskList {
+		if match(disk) {
+			matchedDisks = append(matchedDisks, disk)
+		}
+	}
+
+	switch len(matchedDisks) {
+	case 0:
+		return "", errors.Errorf("no disk found matching %v selector in %v vm", diskSelector, appVMMoid)
+	case 1:
+		log.InfoWithValues("[Info]: The disk selector is resolved as follows", logrus.Fields{
+			"VM ID":         appVMMoid,
+			"Disk Selector": diskSelector,
+			"Disk ID":       matchedDisks[0].Disk,
+			"Disk Label":    matchedDisks[0].Label,
+			"Disk Position": matchedDisks[0].Position(),
+		})
+		return matchedDisks[0].Disk, nil
+	default:
+		return "", errors.Errorf("multiple disks found matching %v selector in %v vm, please provide the controller type along with the position", diskSelector, appVMMoid)
+	}
+}
+
+// GetDiskIdByPath returns the id of the disk backed by the given vmdk file, if it is attached to the given vm
+// it returns an empty disk id, if the vmdk file is not attached to the vm
+func (c *VcenterClient) GetDiskIdByPath(ctx context.Context, appVMMoid, diskPath string) (string, error) {
+
+	diskList, err := c.ListDisks(ctx, appVMMoid)
+	if err != nil {
+		return "", err
+	}
+
+	for _, disk := range diskList {
+		if disk.VMDKFile == diskPath {
+			return disk.Disk, nil
+		}
+	}
+
+	return "", nil
+}
@@ -1,56 +1,21 @@
 package vmware
 
 import (
-	"bytes"
-	"crypto/tls"
-	"encoding/json"
-	"fmt"
-	"io/ioutil"
-	"net/http"
-
-	vmwareLib "github.com/litmuschaos/litmus-go/pkg/cloud/vmware"
+	"context"
+	"strings"
+
 	"github.com/litmuschaos/litmus-go/pkg/log"
 	"github.com/pkg/errors"
 	"github.com/sirupsen/logrus"
 )
 
 // DiskDetach will detach a disk from a VM
-func DiskDetach(vcenterServer, appVMMoid, diskId, cookie string) error {
+func (c *VcenterClient) DiskDetach(ctx context.Context, appVMMoid, diskId string) error {
 
-	req, err := http.NewRequest("DELETE", "https://"+vcenterServer+"/rest/vcenter/vm/"+appVMMoid+"/hardware/disk/"+diskId, nil)
-	if err != nil {
+	if _, err := c.do(ctx, "DELETE", "/vcenter/vm/"+appVMMoid+"/hardware/disk/"+diskId, nil, "disk detachment"); err != nil {
 		return err
 	}
 
-	req.Header.Set("Content-Type", "application/json")
-	req.Header.Set("Cookie", cookie)
-	tr := &http.Transport{
-		TLSClientConfig: &tls.Config{InsecureSkipVerify: true},
-	}
-
-	client := &http.Client{Transport: tr}
-	resp, err := client.Do(req)
-	if err != nil {
-		return err
-	}
-
-	defer resp.Body.Close()
-
-	if resp.StatusCode != http.StatusOK {
-		var errorResponse vmwareLib.ErrorResponse
-
-		body, err := ioutil.ReadAll(resp.Body)
-		if err != nil {
-			return err
-		}
-
-		if err = json.Unmarshal(body, &errorResponse); err != nil {
-			return err
-		}
-
-		return errors.Errorf("error during disk detachment: %s", errorResponse.MsgValue.MsgMessages[0].MsgDefaultMessage)
-	}
-
 	log.InfoWithValues("Detached disk having:", logrus.Fields{
 		"VM ID":   appVMMoid,
 		"Disk ID": diskId,
@@ -60,110 +25,132 @@ func DiskDetach(vcenterServer, appVMMoid, diskId, cookie string) error {
 }
 
 // DiskAttach will attach a disk to a VM
-func DiskAttach(vcenterServer, appVMMoid, diskPath, cookie string) error {
-
-	type AttachDiskResponse struct {
-		MsgValue string `json:"value"`
-	}
+// it returns the id of the attached disk, which may differ from the id of the detached disk
+func (c *VcenterClient) DiskAttach(ctx context.Context, appVMMoid, diskPath string) (string, error) {
+	return c.attachDisk(ctx, appVMMoid, DiskInfo{VMDKFile: diskPath})
+}
 
-	jsonString := fmt.Sprintf(`{"spec":{"backing":{"type":"VMDK_FILE","vmdk_file":"%s"}}}`, diskPath)
+// DiskAttachAt will attach a disk to a VM at the controller and unit of the given disk details
+// the details are generally captured before the disk detachment, so that the disk is reattached to its original slot
+// it returns the id of the attached disk, which may differ from the id of the detached disk
+// an error is returned only if the disk is not attached
+func (c *VcenterClient) DiskAttachAt(ctx context.Context, appVMMoid string, diskInfo DiskInfo) (string, error) {
 
-	req, err := http.NewRequest("POST", "https://"+vcenterServer+"/rest/vcenter/vm/"+appVMMoid+"/hardware/disk", bytes.NewBuffer([]byte(jsonString)))
-	if err != nil {
-		return err
-	}
-
-	req.Header.Set("Content-Type", "application/json")
-	req.Header.Set("Cookie", cookie)
-	tr := &http.Transport{
-		TLSClientConfig: &tls.Config{InsecureSkipVerify: true},
+	if diskInfo.Type == "" {
+		return "", errors.Errorf("controller type of %v disk is not known", diskInfo.VMDKFile)
 	}
 
-	client := &http.Client{Transport: tr}
-	resp, err := client.Do(req)
+	diskId, err := c.attachDisk(ctx, appVMMoid, diskInfo)
 	if err != nil {
-		return err
+		return "", err
 	}
 
-	defer resp.Body.Close()
-
-	body, err := ioutil.ReadAll(resp.Body)
+	// verify that the disk is attached to its original slot
+	// the disk is attached at this point, so a failed verification is only reported, the callers still track the attached disk id
+	attachedDisk, err := c.GetDiskInfo(ctx, appVMMoid, diskId)
 	if err != nil {
-		return err
+		log.Warnf("[Warning]: unable to verify the slot of the reattached %v disk, err: %v", diskInfo.VMDKFile, err)
+		return diskId, nil
 	}
 
-	if resp.StatusCode != http.StatusOK {
-		var errorResponse vmwareLib.ErrorResponse
-
-		if err = json.Unmarshal(body, &errorResponse); err != nil {
-			return err
-		}
-
-		return errors.Errorf("error during disk attachment: %s", errorResponse.MsgValue.MsgMessages[0].MsgDefaultMessage)
+	if !strings.EqualFold(attachedDisk.Type, diskInfo.Type) || attachedDisk.Bus != diskInfo.Bus || attachedDisk.Unit != diskInfo.Unit {
+		log.Warnf("[Warning]: %v disk is attached at %v instead of %v", diskInfo.VMDKFile, attachedDisk.Position(), diskInfo.Position())
 	}
 
-	var response AttachDiskResponse
-	if err = json.Unmarshal(body, &response); err != nil {
-		return err
+	if diskInfo.Disk != "" && diskInfo.Disk != diskId {
+		log.Warnf("[Warning]: %v disk is reattached with %v device key, previously %v", diskInfo.VMDKFile, diskId, diskInfo.Disk)
 	}
 
-	log.InfoWithValues("Attached disk having:", logrus.Fields{
-		"VM ID":   appVMMoid,
-		"Disk ID": response.MsgValue,
-	})
-
-	return nil
+	return diskId, nil
 }
 
-// GetDiskPath returns the path of the VMDK disk file for a given disk id
-func GetDiskPath(vcenterServer, appVMMoid, diskId, cookie string) (string, error) {
-
-	type DiskInfo struct {
-		MsgValue struct {
-			MsgBacking struct {
-				MsgVMDKFile string `json:"vmdk_file"`
-			} `json:"backing"`
-		} `json:"value"`
+// attachDisk attaches the vmdk file of the given disk details to the vm and returns the id of the attached disk
+// the disk is pinned to the controller and unit of the disk details, if the controller type is provided
+func (c *VcenterClient) attachDisk(ctx context.Context, appVMMoid string, diskInfo DiskInfo) (string, error) {
+
+	type DiskBacking struct {
+		MsgType     string `json:"type"`
+		MsgVMDKFile string `json:"vmdk_file"`
+	}
+
+	type DiskAddress struct {
+		MsgBus  int `json:"bus"`
+		MsgUnit int `json:"unit"`
+	}
+
+	type DiskCreateSpec struct {
+		MsgType string       `json:"type,omitempty"`
+		MsgSCSI *DiskAddress `json:"scsi,omitempty"`
+		MsgSATA *DiskAddress `json:"sata,omitempty"`
+		MsgNVME *DiskAddress `json:"nvme,omitempty"`
+		MsgIDE  *struct {
+			MsgPrimary bool `json:"primary"`
+			MsgMaster  bool `json:"master"`
+		} `json:"ide,omitempty"`
+		MsgBacking DiskBacking `json:"backing"`
+	}
+
+	spec := DiskCreateSpec{
+		MsgBacking: DiskBacking{
+			MsgType:     "VMDK_FILE",
+			MsgVMDKFile: diskInfo.VMDKFile,
+		},
+	}
+
+	address := &DiskAddress{MsgBus: diskInfo.Bus, MsgUnit: diskInfo.Unit}
+	switch strings.ToUpper(diskInfo.Type) {
+	case "":
+		// the controller and unit are chosen by the vcenter server
+	case "SCSI":
+		spec.MsgSCSI = address
+	case "SATA":
+		spec.MsgSATA = address
+	case "NVME":
+		spec.MsgNVME = address
+	case "IDE":
+		// ide disks are addressed as primary/secondary channel and master/slave device
+		spec.MsgIDE = &struct {
+			MsgPrimary bool `json:"primary"`
+			MsgMaster  bool `json:"master"`
+		}{
+			MsgPrimary: diskInfo.Bus == 0,
+			MsgMaster:  diskInfo.Unit == 0,
+		}
+	default:
+		return "", errors.Errorf("%v controller type is not supported", diskInfo.Type)
 	}
+	spec.MsgType = strings.ToUpper(diskInfo.Type)
 
-	req, err := http.NewRequest("GET", "https://"+vcenterServer+"/rest/vcenter/vm/"+appVMMoid+"/hardware/disk/"+diskId, nil)
+	payload, err := c.encodeSpec(spec)
 	if err != nil {
 		return "", err
 	}
 
-	req.Header.Set("Content-Type", "application/json")
-	req.Header.Set("Cookie", cookie)
-	tr := &http.Transport{
-		TLSClientConfig: &tls.Config{InsecureSkipVerify: true},
-	}
-
-	client := &http.Client{Transport: tr}
-	resp, err := client.Do(req)
+	body, err := c.do(ctx, "POST", "/vcenter/vm/"+appVMMoid+"/hardware/disk", payload, "disk attachment")
 	if err != nil {
 		return "", err
 	}
 
-	defer resp.Body.Close()
-
-	body, err := ioutil.ReadAll(resp.Body)
-	if err != nil {
+	var diskId string
+	if err = c.decodeValue(body, &diskId); err != nil {
 		return "", err
 	}
 
-	if resp.StatusCode != http.StatusOK {
-		var errorResponse vmwareLib.ErrorResponse
+	log.InfoWithValues("Attached disk having:", logrus.Fields{
+		"VM ID":   appVMMoid,
+		"Disk ID": diskId,
+	})
 
-		if err = json.Unmarshal(body, &errorResponse); err != nil {
-			return "", err
-		}
+	return diskId, nil
+}
 
-		return "", errors.Errorf("error during disk information fetch: %s", errorResponse.MsgValue.MsgMessages[0].MsgDefaultMessage)
-	}
+// GetDiskPath returns the path of the VMDK disk file for a given disk id
+func (c *VcenterClient) GetDiskPath(ctx context.Context, appVMMoid, diskId string) (string, error) {
 
-	var diskInfo DiskInfo
-	if err = json.Unmarshal(body, &diskInfo); err != nil {
+	diskInfo, err := c.GetDiskInfo(ctx, appVMMoid, diskId)
+	if err != nil {
 		return "", err
 	}
 
-	return diskInfo.MsgValue.MsgBacking.MsgVMDKFile, nil
+	return diskInfo.VMDKFile, nil
 }
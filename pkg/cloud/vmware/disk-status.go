@@ -1,117 +1,74 @@
 package vmware
 
 import (
-	"crypto/tls"
-	"encoding/json"
-	"io/ioutil"
-	"net/http"
+	"context"
 	"strings"
-	"time"
 
-	vmwareLib "github.com/litmuschaos/litmus-go/pkg/cloud/vmware"
 	"github.com/litmuschaos/litmus-go/pkg/log"
-	"github.com/litmuschaos/litmus-go/pkg/utils/retry"
 	"github.com/pkg/errors"
 	"github.com/sirupsen/logrus"
 )
 
 // WaitForDiskDetachment will wait for the disk to completely detach from the VM
-func WaitForDiskDetachment(vcenterServer, appVMMoid, diskId, cookie string, delay, timeout int) error {
+func (c *VcenterClient) WaitForDiskDetachment(ctx context.Context, appVMMoid, diskId string, delay, timeout int) error {
 
 	log.Info("[Status]: Checking disk status for detachment")
-	return retry.
-		Times(uint(timeout / delay)).
-		Wait(time.Duration(delay) * time.Second).
-		Try(func(attempt uint) error {
+	return PollUntil(ctx, delay, timeout, func(attempt uint) error {
 
-			diskState, err := GetDiskState(vcenterServer, appVMMoid, diskId, cookie)
-			if err != nil {
-				return errors.Errorf("failed to get the disk state")
-			}
-
-			if diskState != "detached" {
-				log.Infof("[Info]: The disk state is %v", diskState)
-				return errors.Errorf("disk is not yet in detached state")
-			}
+		diskState, err := c.GetDiskState(ctx, appVMMoid, diskId)
+		if err != nil {
+			return errors.Errorf("failed to get the disk state")
+		}
 
+		if diskState != "detached" {
 			log.Infof("[Info]: The disk state is %v", diskState)
-			return nil
-		})
+			return errors.Errorf("disk is not yet in detached state")
+		}
+
+		log.Infof("[Info]: The disk state is %v", diskState)
+		return nil
+	})
 }
 
 // WaitForDiskAttachment will wait for the disk to get attached to the VM
-func WaitForDiskAttachment(vcenterServer, appVMMoid, diskId, cookie string, delay, timeout int) error {
+func (c *VcenterClient) WaitForDiskAttachment(ctx context.Context, appVMMoid, diskId string, delay, timeout int) error {
 
 	log.Info("[Status]: Checking disk status for attachment")
-	return retry.
-		Times(uint(timeout / delay)).
-		Wait(time.Duration(delay) * time.Second).
-		Try(func(attempt uint) error {
-
-			diskState, err := GetDiskState(vcenterServer, appVMMoid, diskId, cookie)
-			if err != nil {
-				return errors.Errorf("failed to get the disk status")
-			}
+	return PollUntil(ctx, delay, timeout, func(attempt uint) error {
 
-			if diskState != "attached" {
-				log.Infof("[Info]: The disk state is %v", diskState)
-				return errors.Errorf("disk is not yet in attached state")
-			}
+		diskState, err := c.GetDiskState(ctx, appVMMoid, diskId)
+		if err != nil {
+			return errors.Errorf("failed to get the disk status")
+		}
 
+		if diskState != "attached" {
 			log.Infof("[Info]: The disk state is %v", diskState)
-			return nil
-		})
+			return errors.Errorf("disk is not yet in attached state")
+		}
+
+		log.Infof("[Info]: The disk state is %v", diskState)
+		return nil
+	})
 }
 
 // GetDiskState will verify if the given disk is attached to the given VM or not
-func GetDiskState(vcenterServer, appVMMoid, diskId, cookie string) (string, error) {
+func (c *VcenterClient) GetDiskState(ctx context.Context, appVMMoid, diskId string) (string, error) {
 
-	type DiskList struct {
-		MsgValue []struct {
-			MsgDisk string `json:"disk"`
-		} `json:"value"`
+	type DiskSummary struct {
+		MsgDisk string `json:"disk"`
 	}
 
-	req, err := http.NewRequest("GET", "https://"+vcenterServer+"/rest/vcenter/vm/"+appVMMoid+"/hardware/disk/", nil)
+	body, err := c.do(ctx, "GET", "/vcenter/vm/"+appVMMoid+"/hardware/disk", nil, "disk state fetch")
 	if err != nil {
 		return "", err
 	}
 
-	req.Header.Set("Content-Type", "application/json")
-	req.Header.Set("Cookie", cookie)
-	tr := &http.Transport{
-		TLSClientConfig: &tls.Config{InsecureSkipVerify: true},
-	}
-
-	client := &http.Client{Transport: tr}
-	resp, err := client.Do(req)
-	if err != nil {
-		return "", err
-	}
-
-	defer resp.Body.Close()
-
-	body, err := ioutil.ReadAll(resp.Body)
-	if err != nil {
-		return "", err
-	}
-
-	if resp.StatusCode != http.StatusOK {
-		var errorResponse vmwareLib.ErrorResponse
-
-		if err = json.Unmarshal(body, &errorResponse); err != nil {
-			return "", err
-		}
-
-		return "", errors.Errorf("error during disk state fetch: %s", errorResponse.MsgValue.MsgMessages[0].MsgDefaultMessage)
-	}
-
-	var diskList DiskList
-	if err = json.Unmarshal(body, &diskList); err != nil {
+	var diskList []DiskSummary
+	if err = c.decodeValue(body, &diskList); err != nil {
 		return "", err
 	}
 
-	for _, disk := range diskList.MsgValue {
+	for _, disk := range diskList {
 
 		if disk.MsgDisk == diskId {
 
@@ -127,10 +84,10 @@ func GetDiskState(vcenterServer, appVMMoid, diskId, cookie string) (string, erro
 	return "detached", nil
 }
 
-//DiskStateCheck will check the attachment state of the given disks
-func DiskStateCheck(vcenterServer, appVMMoids, diskIds, cookie string) error {
+// DiskStateCheck will check the attachment state of the given disks
+func (c *VcenterClient) DiskStateCheck(ctx context.Context, appVMMoids, diskIds string) error {
 
-	if vcenterServer == "" {
+	if c.server == "" {
 		return errors.Errorf("no vcenter server provided, please provide the server url")
 	}
 
@@ -150,7 +107,7 @@ func DiskStateCheck(vcenterServer, appVMMoids, diskIds, cookie string) error {
 
 	for i := range diskIdList {
 
-		diskState, err := GetDiskState(vcenterServer, appVMMoidList[i], diskIdList[i], cookie)
+		diskState, err := c.GetDiskState(ctx, appVMMoidList[i], diskIdList[i])
 
 		if err != nil {
 			return errors.Errorf("failed to get the disk %v in attached state, err: %v", diskIdList[i], err.Error())
@@ -0,0 +1,329 @@
+package vmware
+
+import (
+	"context"
+	"sort"
+
+	"github.com/litmuschaos/litmus-go/pkg/log"
+	"github.com/pkg/errors"
+	"github.com/sirupsen/logrus"
+	"github.com/vmware/govmomi/object"
+	"github.com/vmware/govmomi/property"
+	"github.com/vmware/govmomi/vim25"
+	"github.com/vmware/govmomi/vim25/mo"
+	"github.com/vmware/govmomi/vim25/types"
+)
+
+// hostRef returns the managed object reference of the given host moid
+func hostRef(hostMoid string) types.ManagedObjectReference {
+	return types.ManagedObjectReference{Type: "HostSystem", Value: hostMoid}
+}
+
+// taskRef returns the managed object reference of the given task moid
+func taskRef(taskMoid string) types.ManagedObjectReference {
+	return types.ManagedObjectReference{Type: "Task", Value: taskMoid}
+}
+
+// GetHostMaintenanceMode returns true if the given host is in maintenance mode
+func (c *VcenterClient) GetHostMaintenanceMode(ctx context.Context, hostMoid string) (bool, error) {
+
+	var host mo.HostSystem
+	if err := c.retrieveProperties(ctx, hostRef(hostMoid), []string{"runtime.inMaintenanceMode"}, &host); err != nil {
+		return false, errors.Errorf("failed to get the maintenance mode of %v host, err: %v", hostMoid, err)
+	}
+
+	return host.Runtime.InMaintenanceMode, nil
+}
+
+// EnterHostMaintenanceMode issues the EnterMaintenanceMode_Task on the given host and returns the task moid
+// the drs migrates the powered on vms, and the powered off vms if evacuatePoweredOffVMs is set, to the other hosts of the cluster
+// the task fails if the host does not enter maintenance mode within the given timeout (in sec)
+func (c *VcenterClient) EnterHostMaintenanceMode(ctx context.Context, hostMoid string, evacuatePoweredOffVMs bool, timeout int) (string, error) {
+
+	var taskMoid string
+	err := c.withVim(ctx, func(vimClient *vim25.Client) error {
+		task, err := object.NewHostSystem(vimClient, hostRef(hostMoid)).EnterMaintenanceMode(ctx, int32(timeout), evacuatePoweredOffVMs, nil)
+		if err != nil {
+			return err
+		}
+		taskMoid = task.Reference().Value
+		return nil
+	})
+	if err != nil {
+		return "", errors.Errorf("failed to enter maintenance mode on %v host, err: %v", hostMoid, err)
+	}
+
+	return taskMoid, nil
+}
+
+// ExitHostMaintenanceMode issues the ExitMaintenanceMode_Task on the given host and returns the task moid
+// the task fails if the host does not exit maintenance mode within the given timeout (in sec)
+func (c *VcenterClient) ExitHostMaintenanceMode(ctx context.Context, hostMoid string, timeout int) (string, error) {
+
+	var taskMoid string
+	err := c.withVim(ctx, func(vimClient *vim25.Client) error {
+		task, err := object.NewHostSystem(vimClient, hostRef(hostMoid)).ExitMaintenanceMode(ctx, int32(timeout))
+		if err != nil {
+			return err
+		}
+		taskMoid = task.Reference().Value
+		return nil
+	})
+	if err != nil {
+		return "", errors.Errorf("failed to exit maintenance mode on %v host, err: %v", hostMoid, err)
+	}
+
+	return taskMoid, nil
+}
+
+// CancelTask cancels the given vcenter task, the tasks which are already completed are ignored
+func (c *VcenterClient) CancelTask(ctx context.Context, taskMoid string) error {
+
+	var task mo.Task
+	if err := c.retrieveProperties(ctx, taskRef(taskMoid), []string{"info.state"}, &task); err != nil {
+		return errors.Errorf("failed to get the state of %v task, err: %v", taskMoid, err)
+	}
+
+	if task.Info.State == types.TaskInfoStateSuccess || task.Info.State == types.TaskInfoStateError {
+		return nil
+	}
+
+	return c.withVim(ctx, func(vimClient *vim25.Client) error {
+		return object.NewTask(vimClient, taskRef(taskMoid)).Cancel(ctx)
+	})
+}
+
+// WaitForTask waits for the given vcenter task to complete
+// it returns the task error without waiting any further, if the task fails
+func (c *VcenterClient) WaitForTask(ctx context.Context, taskMoid string, delay, timeout int) error {
+
+	var taskErr error
+
+	log.Infof("[Status]: Checking %v task for completion", taskMoid)
+	err := PollUntil(ctx, delay, timeout, func(attempt uint) error {
+
+		var task mo.Task
+		if err := c.retrieveProperties(ctx, taskRef(taskMoid), []string{"info"}, &task); err != nil {
+			return errors.Errorf("failed to get the task details, err: %v", err)
+		}
+
+		log.Infof("[Info]: The task state is %v, progress: %v%%", task.Info.State, task.Info.Progress)
+		switch task.Info.State {
+		case types.TaskInfoStateSuccess:
+			return nil
+		case types.TaskInfoStateError:
+			taskErr = errors.Errorf("%v task failed", taskMoid)
+			if task.Info.Error != nil {
+				taskErr = errors.Errorf("%v task failed, err: %v", taskMoid, task.Info.Error.LocalizedMessage)
+			}
+			return nil
+		default:
+			return errors.Errorf("task is not yet completed")
+		}
+	})
+	if err != nil {
+		return err
+	}
+
+	return taskErr
+}
+
+// HostEvacuationCheck verifies that the vms of the given host can be evacuated to the other hosts of its cluster
+// it checks that the host is available, the drs of the cluster is fully automated and
+// the other available hosts of the cluster have the capacity to run the current workload of the cluster
+func (c *VcenterClient) HostEvacuationCheck(ctx context.Context, host HostSummary) error {
+
+	if host.MsgConnectionState != HostConnectionStateConnected || host.MsgPowerState != HostPowerStateOn {
+		return errors.Errorf("%v host is not available, host is in %v connection state and %v power state", host.MsgName, host.MsgConnectionState, host.MsgPowerState)
+	}
+
+	var targetHost mo.HostSystem
+	if err := c.retrieveProperties(ctx, hostRef(host.MsgHost), []string{"parent", "runtime.inMaintenanceMode"}, &targetHost); err != nil {
+		return errors.Errorf("failed to get the details of %v host, err: %v", host.MsgName, err)
+	}
+
+	if targetHost.Runtime.InMaintenanceMode {
+		return errors.Errorf("%v host is already in maintenance mode", host.MsgName)
+	}
+
+	if targetHost.Parent == nil || targetHost.Parent.Type != "ClusterComputeResource" {
+		return errors.Errorf("%v host is not part of any cluster", host.MsgName)
+	}
+
+	var cluster mo.ClusterComputeResource
+	if err := c.retrieveProperties(ctx, *targetHost.Parent, []string{"name", "host", "configurationEx"}, &cluster); err != nil {
+		return errors.Errorf("failed to get the cluster details of %v host, err: %v", host.MsgName, err)
+	}
+
+	if err := clusterDRSCheck(cluster); err != nil {
+		return err
+	}
+
+	return c.clusterCapacityCheck(ctx, cluster, host.MsgHost)
+}
+
+// clusterDRSCheck verifies that the drs of the cluster is enabled and fully automated
+// the vms are not migrated off the host entering maintenance mode by a partially automated or manual drs
+func clusterDRSCheck(cluster mo.ClusterComputeResource) error {
+
+	clusterConfig, ok := cluster.ConfigurationEx.(*types.ClusterConfigInfoEx)
+	if !ok {
+		return errors.Errorf("failed to get the drs config of %v cluster", cluster.Name)
+	}
+
+	drsConfig := clusterConfig.DrsConfig
+	if drsConfig.Enabled == nil || !*drsConfig.Enabled {
+		return errors.Errorf("drs is not enabled on %v cluster", cluster.Name)
+	}
+
+	if drsConfig.DefaultVmBehavior != types.DrsBehaviorFullyAutomated {
+		return errors.Errorf("drs automation level of %v cluster is %v, it should be %v", cluster.Name, drsConfig.DefaultVmBehavior, types.DrsBehaviorFullyAutomated)
+	}
+
+	return nil
+}
+
+// hostCapacity is the cpu (MHz) and memory (MB) capacity of a cluster host
+type hostCapacity struct {
+	moid   string
+	cpu    int64
+	memory int64
+}
+
+// clusterCapacityCheck verifies that the cpu and memory capacity of the other available hosts of the cluster,
+// excluding the capacity reserved by the ha admission control, exceeds the cpu and memory usage of all the available hosts,
+// including the host entering maintenance mode
+func (c *VcenterClient) clusterCapacityCheck(ctx context.Context, cluster mo.ClusterComputeResource, hostMoid string) error {
+
+	var hostList []mo.HostSystem
+	err := c.withVim(ctx, func(vimClient *vim25.Client) error {
+		return property.DefaultCollector(vimClient).Retrieve(ctx, cluster.Host, []string{"name", "summary"}, &hostList)
+	})
+	if err != nil {
+		return errors.Errorf("failed to get the hosts of %v cluster, err: %v", cluster.Name, err)
+	}
+
+	// cpu is measured in MHz and memory in MB
+	var spareHosts []hostCapacity
+	var cpuCapacity, memoryCapacity, cpuUsage, memoryUsage int64
+	for _, clusterHost := range hostList {
+
+		summary := clusterHost.Summary
+		if summary.Runtime == nil {
+			return errors.Errorf("failed to get the runtime summary of %v host of %v cluster", clusterHost.Name, cluster.Name)
+		}
+
+		if summary.Runtime.ConnectionState != types.HostSystemConnectionStateConnected || summary.Runtime.PowerState != types.HostSystemPowerStatePoweredOn || summary.Runtime.InMaintenanceMode {
+			continue
+		}
+
+		// the capacity of the cluster can not be verified if the hardware or the usage of an available host is not reported
+		if summary.Hardware == nil {
+			return errors.Errorf("failed to get the hardware summary of %v host of %v cluster", clusterHost.Name, cluster.Name)
+		}
+
+		if summary.QuickStats.OverallCpuUsage == 0 && summary.QuickStats.OverallMemoryUsage == 0 {
+			return errors.Errorf("failed to get the usage stats of %v host of %v cluster", clusterHost.Name, cluster.Name)
+		}
+
+		cpuUsage += int64(summary.QuickStats.OverallCpuUsage)
+		memoryUsage += int64(summary.QuickStats.OverallMemoryUsage)
+
+		if clusterHost.Reference().Value == hostMoid {
+			continue
+		}
+
+		capacity := hostCapacity{
+			moid:   clusterHost.Reference().Value,
+			cpu:    int64(summary.Hardware.CpuMhz) * int64(summary.Hardware.NumCpuCores),
+			memory: summary.Hardware.MemorySize / (1024 * 1024),
+		}
+		spareHosts = append(spareHosts, capacity)
+		cpuCapacity += capacity.cpu
+		memoryCapacity += capacity.memory
+	}
+
+	cpuReserve, memoryReserve := haAdmissionControlReserve(cluster, spareHosts)
+
+	log.InfoWithValues("[Info]: The capacity of the cluster without the target host is as follows", logrus.Fields{
+		"Cluster":                cluster.Name,
+		"Available Hosts":        len(spareHosts),
+		"CPU Capacity (MHz)":     cpuCapacity,
+		"CPU HA Reserve (MHz)":   cpuReserve,
+		"CPU Usage (MHz)":        cpuUsage,
+		"Memory Capacity (MB)":   memoryCapacity,
+		"Memory HA Reserve (MB)": memoryReserve,
+		"Memory Usage (MB)":      memoryUsage,
+	})
+
+	if len(spareHosts) == 0 {
+		return errors.Errorf("no other available host found in %v cluster to evacuate the vms", cluster.Name)
+	}
+
+	if cpuCapacity-cpuReserve < cpuUsage {
+		return errors.Errorf("%v cluster does not have the spare cpu capacity to evacuate the host, capacity: %vMHz, ha reserve: %vMHz, usage: %vMHz", cluster.Name, cpuCapacity, cpuReserve, cpuUsage)
+	}
+
+	if memoryCapacity-memoryReserve < memoryUsage {
+		return errors.Errorf("%v cluster does not have the spare memory capacity to evacuate the host, capacity: %vMB, ha reserve: %vMB, usage: %vMB", cluster.Name, memoryCapacity, memoryReserve, memoryUsage)
+	}
+
+	return nil
+}
+
+// haAdmissionControlReserve returns the cpu and memory capacity of the given hosts which is reserved by the ha admission control of the cluster
+// the vms can not be powered on or migrated into the reserved capacity, so it is not available to evacuate the host
+func haAdmissionControlReserve(cluster mo.ClusterComputeResource, hosts []hostCapacity) (int64, int64) {
+
+	clusterConfig, ok := cluster.ConfigurationEx.(*types.ClusterConfigInfoEx)
+	if !ok {
+		return 0, 0
+	}
+
+	dasConfig := clusterConfig.DasConfig
+	if dasConfig.Enabled == nil || !*dasConfig.Enabled || dasConfig.AdmissionControlEnabled == nil || !*dasConfig.AdmissionControlEnabled {
+		return 0, 0
+	}
+
+	var cpuReserve, memoryReserve int64
+	switch policy := dasConfig.AdmissionControlPolicy.(type) {
+	case *types.ClusterFailoverResourcesAdmissionControlPolicy:
+		// a percentage of the cluster capacity is reserved
+		for _, host := range hosts {
+			cpuReserve += host.cpu * int64(policy.CpuFailoverResourcesPercent) / 100
+			memoryReserve += host.memory * int64(policy.MemoryFailoverResourcesPercent) / 100
+		}
+	case *types.ClusterFailoverHostAdmissionControlPolicy:
+		// the dedicated failover hosts do not run any vm
+		for _, host := range hosts {
+			for _, failoverHost := range policy.FailoverHosts {
+				if failoverHost.Value == host.moid {
+					cpuReserve += host.cpu
+					memoryReserve += host.memory
+				}
+			}
+		}
+	case *types.ClusterFailoverLevelAdmissionControlPolicy:
+		// the capacity to tolerate the given number of host failures is reserved, the largest hosts are considered to be failed
+		cpuReserve = largestCapacities(hosts, int(policy.FailoverLevel), func(host hostCapacity) int64 { return host.cpu })
+		memoryReserve = largestCapacities(hosts, int(policy.FailoverLevel), func(host hostCapacity) int64 { return host.memory })
+	}
+
+	return cpuReserve, memoryReserve
+}
+
+// largestCapacities returns the sum of the given number of largest capacities of the hosts
+func largestCapacities(hosts []hostCapacity, count int, capacity func(hostCapacity) int64) int64 {
+
+	capacities := make([]int64, 0, len(hosts))
+	for _, host := range hosts {
+		capacities = append(capacities, capacity(host))
+	}
+	sort.Slice(capacities, func(i, j int) bool { return capacities[i] > capacities[j] })
+
+	var total int64
+	for i := 0; i < count && i < len(capacities); i++ {
+		total += capacities[i]
+	}
+	return total
+}
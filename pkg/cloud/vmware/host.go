@@ -0,0 +1,74 @@
+package vmware
+
+import (
+	"context"
+	"net/url"
+
+	"github.com/litmuschaos/litmus-go/pkg/log"
+	"github.com/pkg/errors"
+	"github.com/sirupsen/logrus"
+)
+
+const (
+	// HostConnectionStateConnected is the connection state of a host connected to the vcenter
+	HostConnectionStateConnected = "CONNECTED"
+	// HostPowerStateOn is the power state of a powered on host
+	HostPowerStateOn = "POWERED_ON"
+)
+
+// HostSummary contains the summary of an esxi host
+type HostSummary struct {
+	MsgHost            string `json:"host"`
+	MsgName            string `json:"name"`
+	MsgConnectionState string `json:"connection_state"`
+	MsgPowerState      string `json:"power_state"`
+}
+
+// GetHost returns the summary of the host with the given name
+func (c *VcenterClient) GetHost(ctx context.Context, hostName string) (HostSummary, error) {
+
+	if hostName == "" {
+		return HostSummary{}, errors.Errorf("no host name provided, please provide the host name")
+	}
+
+	filters := url.Values{}
+	filters.Set(c.APIFlavour().filterParam("names"), hostName)
+
+	var hostList []HostSummary
+	if err := c.list(ctx, "/vcenter/host", filters, "host lookup", &hostList); err != nil {
+		return HostSummary{}, err
+	}
+
+	switch len(hostList) {
+	case 0:
+		return HostSummary{}, errors.Errorf("no host found with %v name", hostName)
+	case 1:
+		log.InfoWithValues("[Info]: The host name is resolved as follows", logrus.Fields{
+			"Host Name": hostName,
+			"Host ID":   hostList[0].MsgHost,
+		})
+		return hostList[0], nil
+	default:
+		return HostSummary{}, errors.Errorf("multiple hosts found with %v name", hostName)
+	}
+}
+
+// GetHostVMMoids returns the moids of the powered on vms running on the given host
+func (c *VcenterClient) GetHostVMMoids(ctx context.Context, hostMoid string) ([]string, error) {
+
+	filters := url.Values{}
+	filters.Set(c.APIFlavour().filterParam("hosts"), hostMoid)
+	filters.Set(c.APIFlavour().filterParam("power_states"), PowerStateOn)
+
+	var vmList []VMSummary
+	if err := c.list(ctx, "/vcenter/vm", filters, "host vm list fetch", &vmList); err != nil {
+		return nil, err
+	}
+
+	var vmMoidList []string
+	for _, vm := range vmList {
+		vmMoidList = append(vmMoidList, vm.MsgVM)
+	}
+
+	return vmMoidList, nil
+}
@@ -0,0 +1,131 @@
+package vmware
+
+import (
+	"context"
+	"regexp"
+	"strings"
+
+	"github.com/litmuschaos/litmus-go/pkg/log"
+	"github.com/pkg/errors"
+	"github.com/sirupsen/logrus"
+)
+
+// macAddressRegex matches the mac address selector, e.g, 00:50:56:aa:bb:cc
+var macAddressRegex = regexp.MustCompile(`^(?i)([0-9a-f]{2}[:-]){5}[0-9a-f]{2}$`)
+
+// NICInfo contains the details of a virtual ethernet adapter
+type NICInfo struct {
+	NIC        string
+	Label      string
+	MACAddress string
+	State      string
+}
+
+// GetNICInfo returns the details of the given virtual ethernet adapter
+func (c *VcenterClient) GetNICInfo(ctx context.Context, appVMMoid, nicId string) (NICInfo, error) {
+
+	type NICDetails struct {
+		MsgLabel      string `json:"label"`
+		MsgMACAddress string `json:"mac_address"`
+		MsgState      string `json:"state"`
+	}
+
+	body, err := c.do(ctx, "GET", "/vcenter/vm/"+appVMMoid+"/hardware/ethernet/"+nicId, nil, "nic information fetch")
+	if err != nil {
+		return NICInfo{}, err
+	}
+
+	var nicDetails NICDetails
+	if err = c.decodeValue(body, &nicDetails); err != nil {
+		return NICInfo{}, err
+	}
+
+	return NICInfo{
+		NIC:        nicId,
+		Label:      nicDetails.MsgLabel,
+		MACAddress: nicDetails.MsgMACAddress,
+		State:      nicDetails.MsgState,
+	}, nil
+}
+
+// ListNICs returns the details of all the virtual ethernet adapters of the given vm
+func (c *VcenterClient) ListNICs(ctx context.Context, appVMMoid string) ([]NICInfo, error) {
+
+	type NICSummary struct {
+		MsgNIC string `json:"nic"`
+	}
+
+	body, err := c.do(ctx, "GET", "/vcenter/vm/"+appVMMoid+"/hardware/ethernet", nil, "nic list fetch")
+	if err != nil {
+		return nil, err
+	}
+
+	var nicSummaryList []NICSummary
+	if err = c.decodeValue(body, &nicSummaryList); err != nil {
+		return nil, err
+	}
+
+	var nicList []NICInfo
+	for _, nicSummary := range nicSummaryList {
+
+		nicInfo, err := c.GetNICInfo(ctx, appVMMoid, nicSummary.MsgNIC)
+		if err != nil {
+			return nil, err
+		}
+
+		nicList = append(nicList, nicInfo)
+	}
+
+	return nicList, nil
+}
+
+// ResolveNICSelector returns the id of the virtual ethernet adapter matching the given selector
+// the selector can be the nic id (4000), the nic label (Network adapter 1) or the mac address (00:50:56:aa:bb:cc)
+func (c *VcenterClient) ResolveNICSelector(ctx context.Context, appVMMoid, nicSelector string) (string, error) {
+
+	if nicSelector == "" {
+		return "", errors.Errorf("no nic selector provided for %v vm", appVMMoid)
+	}
+
+	nicList, err := c.ListNICs(ctx, appVMMoid)
+	if err != nil {
+		return "", err
+	}
+
+	var match func(nic NICInfo) bool
+
+	switch {
+	case macAddressRegex.MatchString(nicSelector):
+		macAddress := strings.ReplaceAll(nicSelector, "-", ":")
+		match = func(nic NICInfo) bool {
+			return strings.EqualFold(nic.MACAddress, macAddress)
+		}
+	default:
+		match = func(nic NICInfo) bool {
+			return nic.NIC == nicSelector || strings.EqualFold(nic.Label, nicSelector)
+		}
+	}
+
+	var matchedNICs []NICInfo
+	for _, nic := range nicList {
+		if match(nic) {
+			matchedNICs = append(matchedNICs, nic)
+		}
+	}
+
+	switch len(matchedNICs) {
+	case 0:
+		return "", errors.Errorf("no nic found matching %v selector in %v vm", nicSelector, appVMMoid)
+	case 1:
+		log.InfoWithValues("[Info]: The nic selector is resolved as follows", logrus.Fields{
+			"VM ID":        appVMMoid,
+			"NIC Selector": nicSelector,
+			"NIC ID":       matchedNICs[0].NIC,
+			"NIC Label":    matchedNICs[0].Label,
+			"MAC Address":  matchedNICs[0].MACAddress,
+		})
+		return matchedNICs[0].NIC, nil
+	default:
+		return "", errors.Errorf("multiple nics found matching %v selector in %v vm, please provide the mac address", nicSelector, appVMMoid)
+	}
+}
@@ -0,0 +1,105 @@
+package vmware
+
+import (
+	"math/rand"
+	"net/http"
+	"time"
+)
+
+const (
+	// defaultMaxRetryDelay is the upper bound of the delay between two retries
+	defaultMaxRetryDelay = 30 * time.Second
+)
+
+// RetryPolicy contains the retry details for the transient vcenter failures
+type RetryPolicy struct {
+	// Attempts is the total number of attempts, including the first one
+	Attempts int
+	// BaseDelay is the delay before the first retry, it is doubled after every retry
+	BaseDelay time.Duration
+	// MaxDelay is the upper bound of the delay between two retries
+	MaxDelay time.Duration
+	// JitterPercentage is the percentage of the delay which is randomised
+	JitterPercentage int
+	// RetryableStatusCodes are the http status codes which are retried
+	RetryableStatusCodes []int
+}
+
+// DefaultRetryPolicy returns the default retry policy for the vcenter api calls
+func DefaultRetryPolicy() RetryPolicy {
+	return RetryPolicy{
+		Attempts:             3,
+		BaseDelay:            2 * time.Second,
+		MaxDelay:             defaultMaxRetryDelay,
+		JitterPercentage:     20,
+		RetryableStatusCodes: []int{http.StatusTooManyRequests, http.StatusBadGateway, http.StatusServiceUnavailable, http.StatusGatewayTimeout},
+	}
+}
+
+// delay returns the backoff delay before the given retry attempt (starting from 1)
+func (policy RetryPolicy) delay(attempt int) time.Duration {
+
+	maxDelay := policy.MaxDelay
+	if maxDelay <= 0 {
+		maxDelay = defaultMaxRetryDelay
+	}
+
+	delay := policy.BaseDelay
+	for i := 1; i < attempt && delay < maxDelay; i++ {
+		delay *= 2
+	}
+	if delay > maxDelay {
+		delay = maxDelay
+	}
+
+	if policy.JitterPercentage > 0 && delay > 0 {
+		jitter := int64(delay) * int64(policy.JitterPercentage) / 100
+		if jitter > 0 {
+			delay += time.Duration(rand.Int63n(2*jitter+1) - jitter)
+		}
+	}
+
+	return delay
+}
+
+// requestNotSentError is returned when the request fails before it is written to the connection
+type requestNotSentError struct {
+	err error
+}
+
+func (e *requestNotSentError) Error() string {
+	return e.err.Error()
+}
+
+// isRetryable checks whether a failed attempt can be retried
+// idempotent requests are retried for connection failures and the retryable status codes,
+// other requests are only retried if the request was not sent or vcenter reports that the resource is busy,
+// as a 429 or 503 response from a proxy does not guarantee that the operation was not performed
+func (policy RetryPolicy) isRetryable(method string, statusCode int, err error) bool {
+
+	idempotent := method == "GET" || method == "DELETE"
+
+	if err != nil {
+		if _, ok := err.(*requestNotSentError); ok {
+			return true
+		}
+		if _, ok := asVcenterError(err); !ok {
+			return idempotent
+		}
+		if IsResourceBusy(err) {
+			return true
+		}
+	}
+
+	return idempotent && policy.hasStatusCode(statusCode)
+}
+
+// hasStatusCode checks whether the given status code is retryable
+func (policy RetryPolicy) hasStatusCode(statusCode int) bool {
+	for _, code := range policy.RetryableStatusCodes {
+		if code == statusCode {
+			return true
+		}
+	}
+	return false
+}
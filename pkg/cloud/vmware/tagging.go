@@ -0,0 +1,253 @@
+package vmware
+
+import (
+	"context"
+	"encoding/json"
+	"sort"
+	"strings"
+
+	"github.com/litmuschaos/litmus-go/pkg/log"
+	"github.com/pkg/errors"
+)
+
+// TagSelector selects the vsphere objects having the given tag
+// an empty category matches the tag of any category
+type TagSelector struct {
+	Category string
+	Tag      string
+}
+
+// String returns the selector in the <category>=<tag> format
+func (selector TagSelector) String() string {
+	if selector.Category == "" {
+		return selector.Tag
+	}
+	return selector.Category + "=" + selector.Tag
+}
+
+// ParseTagSelectors parses the given comma separated tag selectors (e.g, env=staging,tier=db)
+// a selector without category (e.g, staging) matches the tag of any category
+func ParseTagSelectors(tagSelectors string) ([]TagSelector, error) {
+
+	var selectorList []TagSelector
+
+	for _, tagSelector := range strings.Split(tagSelectors, ",") {
+
+		tagSelector = strings.TrimSpace(tagSelector)
+		if tagSelector == "" {
+			continue
+		}
+
+		selector := TagSelector{Tag: tagSelector}
+		if index := strings.Index(tagSelector, "="); index != -1 {
+			selector.Category = strings.TrimSpace(tagSelector[:index])
+			selector.Tag = strings.TrimSpace(tagSelector[index+1:])
+		}
+
+		if selector.Tag == "" {
+			return nil, errors.Errorf("%v is not a valid tag selector, expected format is <category>=<tag> or <tag>", tagSelector)
+		}
+
+		selectorList = append(selectorList, selector)
+	}
+
+	if len(selectorList) == 0 {
+		return nil, errors.Errorf("no tag selector provided, please provide the tag selectors")
+	}
+
+	return selectorList, nil
+}
+
+// GetVMMoidsByTags returns the moids of the vms having all the tags matched by the given selectors
+func (c *VcenterClient) GetVMMoidsByTags(ctx context.Context, selectorList []TagSelector) ([]string, error) {
+
+	var vmMoids map[string]bool
+
+	for _, selector := range selectorList {
+
+		tagIds, err := c.getTagIds(ctx, selector)
+		if err != nil {
+			return nil, err
+		}
+
+		if len(tagIds) == 0 {
+			return nil, errors.Errorf("no tag found matching %v", selector.String())
+		}
+
+		taggedVMs, err := c.getTaggedVMs(ctx, tagIds)
+		if err != nil {
+			return nil, err
+		}
+
+		// the vms must match all the selectors
+		if vmMoids == nil {
+			vmMoids = taggedVMs
+			continue
+		}
+		for vmMoid := range vmMoids {
+			if !taggedVMs[vmMoid] {
+				delete(vmMoids, vmMoid)
+			}
+		}
+	}
+
+	var vmMoidList []string
+	for vmMoid := range vmMoids {
+		vmMoidList = append(vmMoidList, vmMoid)
+	}
+	sort.Strings(vmMoidList)
+
+	log.Infof("[Info]: The vms matching the tag selectors are: %v", vmMoidList)
+	return vmMoidList, nil
+}
+
+// getTagIds returns the ids of the tags matching the given selector
+func (c *VcenterClient) getTagIds(ctx context.Context, selector TagSelector) ([]string, error) {
+
+	type TagInfo struct {
+		MsgName       string `json:"name"`
+		MsgCategoryId string `json:"category_id"`
+	}
+
+	flavour := c.APIFlavour()
+
+	var (
+		candidateTagIds []string
+		categoryId      string
+		err             error
+	)
+
+	if selector.Category != "" {
+
+		if categoryId, err = c.getCategoryId(ctx, selector.Category); err != nil {
+			return nil, err
+		}
+
+		// the /rest api expects the category id in the path, whereas the /api api expects it in the payload
+		path := flavour.taggingPath("/tag", categoryId, "list-tags-for-category")
+		var payload []byte
+		if flavour == APIFlavourAPI {
+			path = flavour.taggingPath("/tag", "", "list-tags-for-category")
+			if payload, err = json.Marshal(map[string]string{"category_id": categoryId}); err != nil {
+				return nil, err
+			}
+		}
+
+		body, err := c.do(ctx, "POST", path, payload, "tag list fetch")
+		if err != nil {
+			return nil, err
+		}
+
+		if err = c.decodeValue(body, &candidateTagIds); err != nil {
+			return nil, err
+		}
+	} else {
+
+		body, err := c.do(ctx, "GET", flavour.taggingPath("/tag", "", ""), nil, "tag list fetch")
+		if err != nil {
+			return nil, err
+		}
+
+		if err = c.decodeValue(body, &candidateTagIds); err != nil {
+			return nil, err
+		}
+	}
+
+	var tagIds []string
+	for _, tagId := range candidateTagIds {
+
+		body, err := c.do(ctx, "GET", flavour.taggingPath("/tag", tagId, ""), nil, "tag information fetch")
+		if err != nil {
+			return nil, err
+		}
+
+		var tagInfo TagInfo
+		if err = c.decodeValue(body, &tagInfo); err != nil {
+			return nil, err
+		}
+
+		if tagInfo.MsgName == selector.Tag && (categoryId == "" || tagInfo.MsgCategoryId == categoryId) {
+			tagIds = append(tagIds, tagId)
+		}
+	}
+
+	return tagIds, nil
+}
+
+// getCategoryId returns the id of the tag category with the given name
+func (c *VcenterClient) getCategoryId(ctx context.Context, categoryName string) (string, error) {
+
+	type CategoryInfo struct {
+		MsgName string `json:"name"`
+	}
+
+	flavour := c.APIFlavour()
+
+	body, err := c.do(ctx, "GET", flavour.taggingPath("/category", "", ""), nil, "tag category list fetch")
+	if err != nil {
+		return "", err
+	}
+
+	var categoryIds []string
+	if err = c.decodeValue(body, &categoryIds); err != nil {
+		return "", err
+	}
+
+	for _, categoryId := range categoryIds {
+
+		body, err := c.do(ctx, "GET", flavour.taggingPath("/category", categoryId, ""), nil, "tag category information fetch")
+		if err != nil {
+			return "", err
+		}
+
+		var categoryInfo CategoryInfo
+		if err = c.decodeValue(body, &categoryInfo); err != nil {
+			return "", err
+		}
+
+		if categoryInfo.MsgName == categoryName {
+			return categoryId, nil
+		}
+	}
+
+	return "", errors.Errorf("no tag category found with %v name", categoryName)
+}
+
+// getTaggedVMs returns the moids of the vms attached to any of the given tags
+func (c *VcenterClient) getTaggedVMs(ctx context.Context, tagIds []string) (map[string]bool, error) {
+
+	type ObjectId struct {
+		MsgId   string `json:"id"`
+		MsgType string `json:"type"`
+	}
+
+	type TagAssociation struct {
+		MsgObjectIds []ObjectId `json:"object_ids"`
+	}
+
+	payload, err := json.Marshal(map[string][]string{"tag_ids": tagIds})
+	if err != nil {
+		return nil, err
+	}
+
+	body, err := c.do(ctx, "POST", c.APIFlavour().taggingPath("/tag-association", "", "list-attached-objects-on-tags"), payload, "tagged objects fetch")
+	if err != nil {
+		return nil, err
+	}
+
+	var tagAssociations []TagAssociation
+	if err = c.decodeValue(body, &tagAssociations); err != nil {
+		return nil, err
+	}
+
+	vmMoids := map[string]bool{}
+	for _, tagAssociation := range tagAssociations {
+		for _, object := range tagAssociation.MsgObjectIds {
+			if object.MsgType == "VirtualMachine" {
+				vmMoids[object.MsgId] = true
+			}
+		}
+	}
+
+	return vmMoids, nil
+}
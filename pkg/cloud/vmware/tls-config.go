@@ -0,0 +1,55 @@
+package vmware
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"io/ioutil"
+
+	"github.com/pkg/errors"
+)
+
+// TLSDetails contains the tls verification details for the vcenter connection
+type TLSDetails struct {
+	CACertPath         string
+	CACert             string
+	ServerName         string
+	InsecureSkipVerify bool
+}
+
+// NewTLSConfig builds the tls config for the vcenter connection
+// the provided ca bundle is appended to the system cert pool, if available
+func NewTLSConfig(tlsDetails TLSDetails) (*tls.Config, error) {
+
+	tlsConfig := &tls.Config{
+		ServerName:         tlsDetails.ServerName,
+		InsecureSkipVerify: tlsDetails.InsecureSkipVerify,
+	}
+
+	if tlsDetails.CACertPath == "" && tlsDetails.CACert == "" {
+		return tlsConfig, nil
+	}
+
+	certPool, err := x509.SystemCertPool()
+	if err != nil || certPool == nil {
+		certPool = x509.NewCertPool()
+	}
+
+	if tlsDetails.CACertPath != "" {
+		caCert, err := ioutil.ReadFile(tlsDetails.CACertPath)
+		if err != nil {
+			return nil, errors.Errorf("unable to read the ca bundle %v, err: %v", tlsDetails.CACertPath, err)
+		}
+		if !certPool.AppendCertsFromPEM(caCert) {
+			return nil, errors.Errorf("no valid certificate found in the ca bundle %v", tlsDetails.CACertPath)
+		}
+	}
+
+	if tlsDetails.CACert != "" {
+		if !certPool.AppendCertsFromPEM([]byte(tlsDetails.CACert)) {
+			return nil, errors.Errorf("no valid certificate found in the provided ca certificate")
+		}
+	}
+
+	tlsConfig.RootCAs = certPool
+	return tlsConfig, nil
+}
@@ -0,0 +1,411 @@
+package vmware
+
+import (
+	"bytes"
+	"context"
+	"crypto/tls"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptrace"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/litmuschaos/litmus-go/pkg/log"
+	"github.com/pkg/errors"
+	"github.com/vmware/govmomi/vim25"
+)
+
+const (
+	// defaultTimeout is the default timeout for a single vcenter api call
+	defaultTimeout = 60 * time.Second
+	// defaultMaxIdleConns is the default number of idle connections kept open to the vcenter server
+	defaultMaxIdleConns = 10
+)
+
+// VcenterClient is a client for the vcenter rest api
+// it owns the server url, the login session and the underlying http client
+type VcenterClient struct {
+	server      string
+	credentials CredentialsProvider
+	timeout     time.Duration
+	tlsConfig   *tls.Config
+	retryPolicy RetryPolicy
+	httpClient  *http.Client
+
+	mu         sync.RWMutex
+	loginMu    sync.Mutex
+	sessionID  string
+	apiFlavour APIFlavour
+
+	// vimMu guards the vim25 soap client, which is created on the first soap call
+	vimMu  sync.Mutex
+	vimCli *vim25.Client
+}
+
+// ClientOption configures a VcenterClient
+type ClientOption func(*VcenterClient)
+
+// WithCredentials sets the credentials used to login to the vcenter server
+func WithCredentials(user, pass string) ClientOption {
+	return WithCredentialsProvider(StaticCredentials(user, pass))
+}
+
+// WithCredentialsProvider sets the provider of the credentials used to login to the vcenter server
+// the provider is invoked for every login, including the session refresh
+func WithCredentialsProvider(credentials CredentialsProvider) ClientOption {
+	return func(c *VcenterClient) {
+		c.credentials = credentials
+	}
+}
+
+// WithTimeout sets the timeout for every vcenter api call
+func WithTimeout(timeout time.Duration) ClientOption {
+	return func(c *VcenterClient) {
+		c.timeout = timeout
+	}
+}
+
+// WithTLSConfig sets the tls config used to verify the vcenter server
+func WithTLSConfig(tlsConfig *tls.Config) ClientOption {
+	return func(c *VcenterClient) {
+		c.tlsConfig = tlsConfig
+	}
+}
+
+// WithRetryPolicy sets the retry policy for the transient vcenter failures
+func WithRetryPolicy(retryPolicy RetryPolicy) ClientOption {
+	return func(c *VcenterClient) {
+		c.retryPolicy = retryPolicy
+	}
+}
+
+// WithHTTPClient replaces the http client used to reach the vcenter server
+// it can be used to inject custom transports or mocks
+func WithHTTPClient(httpClient *http.Client) ClientOption {
+	return func(c *VcenterClient) {
+		c.httpClient = httpClient
+	}
+}
+
+// WithSession sets an already established session id
+func WithSession(sessionID string) ClientOption {
+	return func(c *VcenterClient) {
+		c.sessionID = sessionID
+	}
+}
+
+// WithAPIFlavour sets the api flavour used by the client
+func WithAPIFlavour(flavour APIFlavour) ClientOption {
+	return func(c *VcenterClient) {
+		c.apiFlavour = flavour
+	}
+}
+
+// NewVcenterClient returns a new client for the given vcenter server
+func NewVcenterClient(server string, opts ...ClientOption) *VcenterClient {
+
+	c := &VcenterClient{
+		server:      server,
+		timeout:     defaultTimeout,
+		retryPolicy: DefaultRetryPolicy(),
+		apiFlavour:  APIFlavourAuto,
+	}
+
+	for _, opt := range opts {
+		opt(c)
+	}
+
+	if c.tlsConfig == nil {
+		c.tlsConfig = &tls.Config{}
+	}
+
+	if c.httpClient == nil {
+		c.httpClient = &http.Client{
+			Transport: &http.Transport{
+				Proxy:               http.ProxyFromEnvironment,
+				TLSClientConfig:     c.tlsConfig,
+				MaxIdleConns:        defaultMaxIdleConns,
+				MaxIdleConnsPerHost: defaultMaxIdleConns,
+				IdleConnTimeout:     90 * time.Second,
+			},
+			Timeout: c.timeout,
+		}
+	}
+
+	return c
+}
+
+// Server returns the vcenter server the client is connected to
+func (c *VcenterClient) Server() string {
+	return c.server
+}
+
+// Session returns the current session id
+func (c *VcenterClient) Session() string {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return c.sessionID
+}
+
+// APIFlavour returns the api flavour used by the client
+// it is APIFlavourAuto until the flavour is detected during login
+func (c *VcenterClient) APIFlavour() APIFlavour {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return c.apiFlavour
+}
+
+// Login creates a new vcenter session using the stored credentials
+// if the api flavour is set to auto, it detects the flavour supported by the vcenter server
+func (c *VcenterClient) Login(ctx context.Context) error {
+
+	if c.server == "" {
+		return errors.Errorf("no vcenter server provided, please provide the server url")
+	}
+
+	flavour := c.APIFlavour()
+	if flavour != APIFlavourAuto {
+		return c.login(ctx, flavour)
+	}
+
+	// the /api session endpoint is not available before vsphere 7.0U2, fallback to the /rest api in that case
+	err := c.login(ctx, APIFlavourAPI)
+	if IsNotFound(err) {
+		log.Info("[Info]: Vcenter /api endpoint is not available, using the /rest endpoint")
+		return c.login(ctx, APIFlavourRest)
+	}
+
+	return err
+}
+
+// login creates a new vcenter session for the given api flavour
+func (c *VcenterClient) login(ctx context.Context, flavour APIFlavour) error {
+
+	if c.credentials == nil {
+		return errors.Errorf("no vcenter credentials provided, please provide the credentials")
+	}
+
+	user, pass, err := c.credentials()
+	if err != nil {
+		return errors.Errorf("unable to get the vcenter credentials, err: %v", err)
+	}
+
+	req, err := c.newRequest(ctx, "POST", flavour.sessionPath(), nil)
+	if err != nil {
+		return err
+	}
+
+	req.SetBasicAuth(user, pass)
+
+	statusCode, body, err := c.send(req)
+	if err != nil {
+		return err
+	}
+
+	if !isSuccess(statusCode) {
+		return decodeError(statusCode, body, "authentication")
+	}
+
+	var sessionID string
+	if err = flavour.decodeValue(body, &sessionID); err != nil {
+		return err
+	}
+
+	c.mu.Lock()
+	c.sessionID = sessionID
+	c.apiFlavour = flavour
+	c.mu.Unlock()
+
+	return nil
+}
+
+// Logout deletes the current vcenter session and the vim25 soap session, if any
+// an already expired session is treated as logged out
+func (c *VcenterClient) Logout(ctx context.Context) error {
+
+	if err := c.vimLogout(ctx); err != nil {
+		log.Errorf("failed to logout from the vcenter soap session, err: %v", err)
+	}
+
+	session := c.Session()
+	if session == "" {
+		return nil
+	}
+
+	statusCode, body, err := c.sendWithSession(ctx, "DELETE", c.APIFlavour().sessionPath(), nil, session)
+	if err != nil {
+		return err
+	}
+
+	if !isSuccess(statusCode) && statusCode != http.StatusUnauthorized {
+		return decodeError(statusCode, body, "logout")
+	}
+
+	c.mu.Lock()
+	c.sessionID = ""
+	c.mu.Unlock()
+
+	return nil
+}
+
+// refreshSession creates a new session if the given session is still the current one
+// concurrent callers holding the same expired session will only trigger a single login
+func (c *VcenterClient) refreshSession(ctx context.Context, expiredSession string) error {
+
+	c.loginMu.Lock()
+	defer c.loginMu.Unlock()
+
+	if c.Session() != expiredSession {
+		return nil
+	}
+
+	log.Info("[Info]: Vcenter session has expired, creating a new session")
+	return c.Login(ctx)
+}
+
+// do sends a request to the given vcenter resource path (e.g, /vcenter/vm) and returns the response body
+// the path is prefixed as per the api flavour, a non 2xx response is decoded into an error, action is used to describe the failing operation
+// the transient failures are retried as per the retry policy of the client
+func (c *VcenterClient) do(ctx context.Context, method, path string, payload []byte, action string) ([]byte, error) {
+
+	attempts := c.retryPolicy.Attempts
+	if attempts < 1 {
+		attempts = 1
+	}
+
+	for attempt := 1; ; attempt++ {
+
+		statusCode, body, err := c.doOnce(ctx, method, path, payload, action)
+		if err == nil {
+			return body, nil
+		}
+
+		// a retried delete finds the resource already deleted, if the previous attempt succeeded on the vcenter but its response was lost
+		if method == "DELETE" && attempt > 1 && IsNotFound(err) {
+			log.Infof("[Retry]: %v already completed by a previous attempt", action)
+			return nil, nil
+		}
+
+		if attempt >= attempts || !c.retryPolicy.isRetryable(method, statusCode, err) {
+			return nil, err
+		}
+
+		delay := c.retryPolicy.delay(attempt)
+		log.Warnf("[Retry]: %v failed, retrying in %v (attempt %v/%v), err: %v", action, delay, attempt+1, attempts, err)
+		select {
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		case <-time.After(delay):
+		}
+	}
+}
+
+// doOnce sends a single request to the given vcenter api path and returns the response status code and body
+// if the session has expired, it logs in again with the stored credentials and resends the request once
+func (c *VcenterClient) doOnce(ctx context.Context, method, path string, payload []byte, action string) (int, []byte, error) {
+
+	session := c.Session()
+
+	statusCode, body, err := c.sendWithSession(ctx, method, c.APIFlavour().resourcePath(path), payload, session)
+	if err != nil {
+		return 0, nil, err
+	}
+
+	if statusCode == http.StatusUnauthorized && c.credentials != nil {
+
+		if err = c.refreshSession(ctx, session); err != nil {
+			return 0, nil, errors.Errorf("failed to refresh the vcenter session, err: %v", err)
+		}
+
+		statusCode, body, err = c.sendWithSession(ctx, method, c.APIFlavour().resourcePath(path), payload, c.Session())
+		if err != nil {
+			return 0, nil, err
+		}
+	}
+
+	if !isSuccess(statusCode) {
+		return statusCode, nil, decodeError(statusCode, body, action)
+	}
+
+	return statusCode, body, nil
+}
+
+// sendWithSession sends a request to the given vcenter api path using the given session
+func (c *VcenterClient) sendWithSession(ctx context.Context, method, path string, payload []byte, session string) (int, []byte, error) {
+
+	req, err := c.newRequest(ctx, method, path, payload)
+	if err != nil {
+		return 0, nil, err
+	}
+
+	if c.APIFlavour() == APIFlavourAPI {
+		req.Header.Set("vmware-api-session-id", session)
+	} else {
+		req.Header.Set("Cookie", "vmware-api-session-id="+session)
+	}
+
+	return c.send(req)
+}
+
+// newRequest builds a new request for the given vcenter api path
+func (c *VcenterClient) newRequest(ctx context.Context, method, path string, payload []byte) (*http.Request, error) {
+
+	var reqBody io.Reader
+	if payload != nil {
+		reqBody = bytes.NewBuffer(payload)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, method, "https://"+c.server+path, reqBody)
+	if err != nil {
+		return nil, err
+	}
+
+	req.Header.Set("Content-Type", "application/json")
+	return req, nil
+}
+
+// send sends the request and returns the response status code and body
+// the failures before the request headers are written are returned as requestNotSentError, as vcenter never received the request
+func (c *VcenterClient) send(req *http.Request) (int, []byte, error) {
+
+	var wroteHeaders int32
+	req = req.WithContext(httptrace.WithClientTrace(req.Context(), &httptrace.ClientTrace{
+		WroteHeaders: func() {
+			atomic.StoreInt32(&wroteHeaders, 1)
+		},
+	}))
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		if atomic.LoadInt32(&wroteHeaders) == 0 {
+			return 0, nil, &requestNotSentError{err: err}
+		}
+		return 0, nil, err
+	}
+
+	defer resp.Body.Close()
+
+	body, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return 0, nil, err
+	}
+
+	return resp.StatusCode, body, nil
+}
+
+// encodeSpec encodes the given spec as request payload for the api flavour of the client
+func (c *VcenterClient) encodeSpec(spec interface{}) ([]byte, error) {
+	return c.APIFlavour().encodeSpec(spec)
+}
+
+// decodeValue decodes the response body into the given value for the api flavour of the client
+func (c *VcenterClient) decodeValue(body []byte, value interface{}) error {
+	return c.APIFlavour().decodeValue(body, value)
+}
+
+// isSuccess checks whether the given status code is a 2xx status code
+func isSuccess(statusCode int) bool {
+	return statusCode >= 200 && statusCode < 300
+}
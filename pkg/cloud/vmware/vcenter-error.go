@@ -0,0 +1,139 @@
+package vmware
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+const (
+	// ErrorTypeNotFound is returned when the referenced resource does not exist
+	ErrorTypeNotFound = "com.vmware.vapi.std.errors.not_found"
+	// ErrorTypeUnauthenticated is returned when the session is missing or has expired
+	ErrorTypeUnauthenticated = "com.vmware.vapi.std.errors.unauthenticated"
+	// ErrorTypeUnauthorized is returned when the user lacks the privileges for the operation
+	ErrorTypeUnauthorized = "com.vmware.vapi.std.errors.unauthorized"
+	// ErrorTypeResourceBusy is returned when the resource is locked by another operation
+	ErrorTypeResourceBusy = "com.vmware.vapi.std.errors.resource_busy"
+	// ErrorTypeServiceUnavailable is returned when the vcenter service is temporarily unavailable
+	ErrorTypeServiceUnavailable = "com.vmware.vapi.std.errors.service_unavailable"
+
+	// maxRawBodyLength is the maximum length of the raw body included in the error message
+	maxRawBodyLength = 256
+)
+
+// VcenterError contains the details of a failed vcenter api call
+type VcenterError struct {
+	Action     string
+	StatusCode int
+	Type       string
+	Messages   []string
+	RawBody    string
+}
+
+// Error returns the error message for the failed vcenter api call
+func (e *VcenterError) Error() string {
+
+	switch {
+	case len(e.Messages) != 0:
+		return fmt.Sprintf("error during %s: %s", e.Action, strings.Join(e.Messages, "; "))
+	case e.Type != "":
+		return fmt.Sprintf("error during %s: %s (status code %d)", e.Action, e.Type, e.StatusCode)
+	default:
+		body := e.RawBody
+		if len(body) > maxRawBodyLength {
+			body = body[:maxRawBodyLength] + "..."
+		}
+		return fmt.Sprintf("error during %s: unexpected response with status code %d: %s", e.Action, e.StatusCode, body)
+	}
+}
+
+// IsNotFound returns true if the error is a vcenter not found error
+func IsNotFound(err error) bool {
+	vcenterErr, ok := asVcenterError(err)
+	return ok && (vcenterErr.Type == ErrorTypeNotFound || vcenterErr.StatusCode == http.StatusNotFound)
+}
+
+// IsUnauthenticated returns true if the error is caused by a missing or expired session
+func IsUnauthenticated(err error) bool {
+	vcenterErr, ok := asVcenterError(err)
+	return ok && (vcenterErr.Type == ErrorTypeUnauthenticated || vcenterErr.StatusCode == http.StatusUnauthorized)
+}
+
+// IsUnauthorized returns true if the error is caused by missing privileges
+func IsUnauthorized(err error) bool {
+	vcenterErr, ok := asVcenterError(err)
+	return ok && (vcenterErr.Type == ErrorTypeUnauthorized || vcenterErr.StatusCode == http.StatusForbidden)
+}
+
+// IsResourceBusy returns true if the error is caused by a resource locked by another operation
+func IsResourceBusy(err error) bool {
+	vcenterErr, ok := asVcenterError(err)
+	return ok && vcenterErr.Type == ErrorTypeResourceBusy
+}
+
+// IsServiceUnavailable returns true if the vcenter service is temporarily unavailable
+func IsServiceUnavailable(err error) bool {
+	vcenterErr, ok := asVcenterError(err)
+	return ok && (vcenterErr.Type == ErrorTypeServiceUnavailable || vcenterErr.StatusCode == http.StatusServiceUnavailable)
+}
+
+// asVcenterError extracts the VcenterError from the given error chain
+func asVcenterError(err error) (*VcenterError, bool) {
+	var vcenterErr *VcenterError
+	if errors.As(err, &vcenterErr) {
+		return vcenterErr, true
+	}
+	return nil, false
+}
+
+// decodeError converts the vcenter error response into a VcenterError
+// it supports both the wrapped /rest and the unwrapped /api error formats
+// a body which is not a vcenter error response (e.g, an html page from a proxy) is kept as raw body
+func decodeError(statusCode int, body []byte, action string) error {
+
+	type ErrorDetails struct {
+		MsgErrorType string `json:"error_type"`
+		MsgMessages  []struct {
+			MsgDefaultMessage string `json:"default_message"`
+		} `json:"messages"`
+	}
+
+	type ErrorResponse struct {
+		ErrorDetails
+		MsgType  string       `json:"type"`
+		MsgValue ErrorDetails `json:"value"`
+	}
+
+	vcenterErr := &VcenterError{
+		Action:     action,
+		StatusCode: statusCode,
+		RawBody:    string(body),
+	}
+
+	var errorResponse ErrorResponse
+	if err := json.Unmarshal(body, &errorResponse); err != nil {
+		return vcenterErr
+	}
+
+	details := errorResponse.MsgValue
+	if details.MsgErrorType == "" && len(details.MsgMessages) == 0 {
+		details = errorResponse.ErrorDetails
+	}
+
+	vcenterErr.Type = errorResponse.MsgType
+	if vcenterErr.Type == "" && details.MsgErrorType != "" {
+		vcenterErr.Type = "com.vmware.vapi.std.errors." + strings.ToLower(details.MsgErrorType)
+	}
+
+	for _, message := range details.MsgMessages {
+		if message.MsgDefaultMessage != "" {
+			vcenterErr.Messages = append(vcenterErr.Messages, message.MsgDefaultMessage)
+		}
+	}
+
+	return vcenterErr
+}
@@ -0,0 +1,121 @@
+package vmware
+
+import (
+	"context"
+	"net/url"
+
+	"github.com/pkg/errors"
+	"github.com/vmware/govmomi/property"
+	"github.com/vmware/govmomi/session"
+	"github.com/vmware/govmomi/vim25"
+	"github.com/vmware/govmomi/vim25/soap"
+	"github.com/vmware/govmomi/vim25/types"
+)
+
+// The maintenance mode of the hosts and the drs config of the clusters are not exposed by the
+// vcenter automation (/rest, /api) apis, they are only available through the vim25 soap api.
+// The soap session shares the server, credentials and tls config of the automation api session.
+
+// vimClient returns the vim25 soap client, the soap session is created on the first use
+func (c *VcenterClient) vimClient(ctx context.Context) (*vim25.Client, error) {
+
+	c.vimMu.Lock()
+	defer c.vimMu.Unlock()
+
+	if c.vimCli != nil {
+		return c.vimCli, nil
+	}
+
+	if c.server == "" {
+		return nil, errors.Errorf("no vcenter server provided, please provide the server url")
+	}
+
+	if c.credentials == nil {
+		return nil, errors.Errorf("no vcenter credentials provided, please provide the credentials")
+	}
+
+	user, pass, err := c.credentials()
+	if err != nil {
+		return nil, errors.Errorf("unable to get the vcenter credentials, err: %v", err)
+	}
+
+	serverURL, err := soap.ParseURL(c.server)
+	if err != nil {
+		return nil, errors.Errorf("unable to parse the vcenter server url, err: %v", err)
+	}
+
+	soapClient := soap.NewClient(serverURL, c.tlsConfig.InsecureSkipVerify)
+	soapClient.DefaultTransport().TLSClientConfig = c.tlsConfig
+	soapClient.Timeout = c.timeout
+
+	vimClient, err := vim25.NewClient(ctx, soapClient)
+	if err != nil {
+		return nil, errors.Errorf("unable to connect to the vcenter soap api, err: %v", err)
+	}
+
+	if err = session.NewManager(vimClient).Login(ctx, url.UserPassword(user, pass)); err != nil {
+		return nil, errors.Errorf("unable to login to the vcenter soap api, err: %v", err)
+	}
+
+	c.vimCli = vimClient
+	return vimClient, nil
+}
+
+// vimLogout deletes the vim25 soap session, if any
+func (c *VcenterClient) vimLogout(ctx context.Context) error {
+
+	c.vimMu.Lock()
+	defer c.vimMu.Unlock()
+
+	if c.vimCli == nil {
+		return nil
+	}
+
+	err := session.NewManager(c.vimCli).Logout(ctx)
+	c.vimCli = nil
+	if err != nil && !isNotAuthenticated(err) {
+		return err
+	}
+	return nil
+}
+
+// withVim runs the given soap call, the soap session is recreated once if it has expired
+func (c *VcenterClient) withVim(ctx context.Context, call func(vimClient *vim25.Client) error) error {
+
+	vimClient, err := c.vimClient(ctx)
+	if err != nil {
+		return err
+	}
+
+	err = call(vimClient)
+	if !isNotAuthenticated(err) {
+		return err
+	}
+
+	c.vimMu.Lock()
+	if c.vimCli == vimClient {
+		c.vimCli = nil
+	}
+	c.vimMu.Unlock()
+
+	if vimClient, err = c.vimClient(ctx); err != nil {
+		return err
+	}
+	return call(vimClient)
+}
+
+// retrieveProperties retrieves the given properties of the managed object into dst
+func (c *VcenterClient) retrieveProperties(ctx context.Context, ref types.ManagedObjectReference, properties []string, dst interface{}) error {
+	return c.withVim(ctx, func(vimClient *vim25.Client) error {
+		return property.DefaultCollector(vimClient).RetrieveOne(ctx, ref, properties, dst)
+	})
+}
+
+// isNotAuthenticated returns true if the soap call failed due to an expired soap session
+func isNotAuthenticated(err error) bool {
+	if err == nil || !soap.IsSoapFault(err) {
+		return false
+	}
+	_, ok := soap.ToSoapFault(err).VimFault().(types.NotAuthenticated)
+	return ok
+}
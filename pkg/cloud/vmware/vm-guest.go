@@ -0,0 +1,194 @@
+package vmware
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	"github.com/litmuschaos/litmus-go/pkg/log"
+	"github.com/pkg/errors"
+	"github.com/sirupsen/logrus"
+	"github.com/vmware/govmomi/vim25/mo"
+	"github.com/vmware/govmomi/vim25/types"
+)
+
+const (
+	// GuestPowerStateRunning is the guest power state of a guest os which is running and sending heartbeats via the vmware tools
+	GuestPowerStateRunning = "RUNNING"
+
+	// GuestActionReboot reboots the guest os
+	GuestActionReboot = "reboot"
+	// GuestActionShutdown shuts down the guest os
+	GuestActionShutdown = "shutdown"
+)
+
+// GuestBootInfo contains the boot details of the vm, which change once its guest os reboots
+type GuestBootInfo struct {
+	BootTime      *time.Time
+	UptimeSeconds int32
+	ToolsRunning  bool
+}
+
+// rebootedSince checks whether the guest os has rebooted since the given boot details were captured
+// the vmware tools stop while the guest os reboots, and the guest uptime restarts once it is back
+func (info GuestBootInfo) rebootedSince(baseline GuestBootInfo) bool {
+
+	if !info.ToolsRunning {
+		return true
+	}
+
+	if info.BootTime != nil && baseline.BootTime != nil && !info.BootTime.Equal(*baseline.BootTime) {
+		return true
+	}
+
+	return info.UptimeSeconds < baseline.UptimeSeconds
+}
+
+// vmRef returns the managed object reference of the given vm moid
+func vmRef(appVMMoid string) types.ManagedObjectReference {
+	return types.ManagedObjectReference{Type: "VirtualMachine", Value: appVMMoid}
+}
+
+// GuestPowerAction issues the given power action (reboot or shutdown) to the guest os of the vm
+// it requires the vmware tools to be running inside the guest os
+func (c *VcenterClient) GuestPowerAction(ctx context.Context, appVMMoid, action string) error {
+
+	switch action {
+	case GuestActionReboot, GuestActionShutdown:
+	default:
+		return errors.Errorf("%v guest power action is not supported, supported values are reboot and shutdown", action)
+	}
+
+	if _, err := c.do(ctx, "POST", c.APIFlavour().actionPath("/vcenter/vm/"+appVMMoid+"/guest/power", action), nil, "guest "+action); err != nil {
+		return err
+	}
+
+	log.InfoWithValues("[Info]: Invoked the guest power action", logrus.Fields{
+		"VM ID":  appVMMoid,
+		"Action": action,
+	})
+
+	return nil
+}
+
+// GetGuestPowerState returns the power state of the guest os of the vm, e.g, RUNNING
+func (c *VcenterClient) GetGuestPowerState(ctx context.Context, appVMMoid string) (string, error) {
+
+	type GuestPowerInfo struct {
+		MsgState string `json:"state"`
+	}
+
+	body, err := c.do(ctx, "GET", "/vcenter/vm/"+appVMMoid+"/guest/power", nil, "guest power state fetch")
+	if err != nil {
+		return "", err
+	}
+
+	var guestPowerInfo GuestPowerInfo
+	if err = c.decodeValue(body, &guestPowerInfo); err != nil {
+		return "", err
+	}
+
+	return guestPowerInfo.MsgState, nil
+}
+
+// GetGuestBootInfo returns the boot time, the guest uptime and the vmware tools status of the vm
+func (c *VcenterClient) GetGuestBootInfo(ctx context.Context, appVMMoid string) (GuestBootInfo, error) {
+
+	var vm mo.VirtualMachine
+	if err := c.retrieveProperties(ctx, vmRef(appVMMoid), []string{"runtime.bootTime", "summary.quickStats.uptimeSeconds", "guest.toolsRunningStatus"}, &vm); err != nil {
+		return GuestBootInfo{}, errors.Errorf("failed to get the boot details of %v vm, err: %v", appVMMoid, err)
+	}
+
+	bootInfo := GuestBootInfo{
+		BootTime:      vm.Runtime.BootTime,
+		UptimeSeconds: vm.Summary.QuickStats.UptimeSeconds,
+	}
+	if vm.Guest != nil {
+		bootInfo.ToolsRunning = vm.Guest.ToolsRunningStatus == string(types.VirtualMachineToolsRunningStatusGuestToolsRunning)
+	}
+
+	return bootInfo, nil
+}
+
+// WaitForGuestHeartbeat waits for the guest os of the vm to report running via the vmware tools
+func (c *VcenterClient) WaitForGuestHeartbeat(ctx context.Context, appVMMoid string, delay, timeout int) error {
+
+	log.Infof("[Status]: Checking the guest heartbeat of %v vm", appVMMoid)
+	return PollUntil(ctx, delay, timeout, func(attempt uint) error {
+
+		guestPowerState, err := c.GetGuestPowerState(ctx, appVMMoid)
+		if err != nil {
+			return errors.Errorf("failed to get the guest power state, err: %v", err)
+		}
+
+		log.Infof("[Info]: The guest power state is %v", guestPowerState)
+		if guestPowerState != GuestPowerStateRunning {
+			return errors.Errorf("guest heartbeat is not yet available")
+		}
+
+		return nil
+	})
+}
+
+// WaitForGuestHeartbeatLoss waits for the guest os of the vm to stop reporting running via the vmware tools
+// a quick reboot may complete between two polls, so a reboot since the given boot details were captured is accepted as well
+// the boot details are not compared if the baseline is nil
+func (c *VcenterClient) WaitForGuestHeartbeatLoss(ctx context.Context, appVMMoid string, baseline *GuestBootInfo, delay, timeout int) error {
+
+	log.Infof("[Status]: Checking the guest heartbeat loss of %v vm", appVMMoid)
+	return PollUntil(ctx, delay, timeout, func(attempt uint) error {
+
+		guestPowerState, err := c.GetGuestPowerState(ctx, appVMMoid)
+		if err != nil {
+			// the guest power state is not available while the vmware tools are down
+			if IsServiceUnavailable(err) {
+				return nil
+			}
+			return errors.Errorf("failed to get the guest power state, err: %v", err)
+		}
+
+		log.Infof("[Info]: The guest power state is %v", guestPowerState)
+		if guestPowerState != GuestPowerStateRunning {
+			return nil
+		}
+
+		if baseline != nil {
+			bootInfo, err := c.GetGuestBootInfo(ctx, appVMMoid)
+			if err != nil {
+				return err
+			}
+			if bootInfo.rebootedSince(*baseline) {
+				log.Infof("[Info]: The guest os of %v vm has rebooted", appVMMoid)
+				return nil
+			}
+		}
+
+		return errors.Errorf("guest heartbeat is still available")
+	})
+}
+
+// GuestHeartbeatCheck verifies that the guest os of all the given vms are running and sending heartbeats
+func (c *VcenterClient) GuestHeartbeatCheck(ctx context.Context, appVMMoids string) error {
+
+	if c.server == "" {
+		return errors.Errorf("no vcenter server provided, please provide the server url")
+	}
+
+	if appVMMoids == "" {
+		return errors.Errorf("no vm id provided, please provide vm id")
+	}
+
+	for _, appVMMoid := range strings.Split(appVMMoids, ",") {
+
+		guestPowerState, err := c.GetGuestPowerState(ctx, appVMMoid)
+		if err != nil {
+			return errors.Errorf("failed to get the guest power state of %v vm, err: %v", appVMMoid, err)
+		}
+
+		if guestPowerState != GuestPowerStateRunning {
+			return errors.Errorf("guest os of %v vm is not running, guest is in %v state, please verify that the vmware tools are running", appVMMoid, guestPowerState)
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,150 @@
+package vmware
+
+import (
+	"context"
+	"net/url"
+	"strings"
+
+	"github.com/litmuschaos/litmus-go/pkg/log"
+	"github.com/pkg/errors"
+	"github.com/sirupsen/logrus"
+)
+
+// VMSummary contains the summary of a vm
+type VMSummary struct {
+	MsgVM         string `json:"vm"`
+	MsgName       string `json:"name"`
+	MsgPowerState string `json:"power_state"`
+}
+
+// GetVMMoids resolves the given comma separated vm names or inventory paths into comma separated vm moids
+func (c *VcenterClient) GetVMMoids(ctx context.Context, vmNames string) (string, error) {
+
+	var appVMMoidList []string
+
+	for _, vmName := range strings.Split(vmNames, ",") {
+
+		appVMMoid, err := c.GetVMMoid(ctx, strings.TrimSpace(vmName))
+		if err != nil {
+			return "", err
+		}
+
+		appVMMoidList = append(appVMMoidList, appVMMoid)
+	}
+
+	return strings.Join(appVMMoidList, ","), nil
+}
+
+// GetVMMoid returns the moid of the vm with the given name or inventory path (e.g, /DC1/vm/folder/app-01)
+func (c *VcenterClient) GetVMMoid(ctx context.Context, vmName string) (string, error) {
+
+	if vmName == "" {
+		return "", errors.Errorf("no vm name provided, please provide the vm name")
+	}
+
+	filters := url.Values{}
+
+	if strings.HasPrefix(vmName, "/") {
+
+		datacenter, folder, name, err := c.resolveInventoryPath(ctx, vmName)
+		if err != nil {
+			return "", err
+		}
+
+		filters.Set(c.APIFlavour().filterParam("datacenters"), datacenter)
+		filters.Set(c.APIFlavour().filterParam("folders"), folder)
+		vmName = name
+	}
+
+	filters.Set(c.APIFlavour().filterParam("names"), vmName)
+
+	var vmList []VMSummary
+	if err := c.list(ctx, "/vcenter/vm", filters, "vm lookup", &vmList); err != nil {
+		return "", err
+	}
+
+	switch len(vmList) {
+	case 0:
+		return "", errors.Errorf("no vm found with %v name", vmName)
+	case 1:
+		log.InfoWithValues("[Info]: The vm name is resolved as follows", logrus.Fields{
+			"VM Name": vmName,
+			"VM ID":   vmList[0].MsgVM,
+		})
+		return vmList[0].MsgVM, nil
+	default:
+		return "", errors.Errorf("multiple vms found with %v name, please provide the inventory path of the vm", vmName)
+	}
+}
+
+// resolveInventoryPath resolves the given vm inventory path (/<datacenter>/vm/<folders>/<vm name>)
+// it returns the datacenter id, the id of the folder containing the vm and the vm name
+func (c *VcenterClient) resolveInventoryPath(ctx context.Context, inventoryPath string) (string, string, string, error) {
+
+	type DatacenterSummary struct {
+		MsgDatacenter string `json:"datacenter"`
+	}
+
+	type FolderSummary struct {
+		MsgFolder string `json:"folder"`
+	}
+
+	segments := strings.Split(strings.Trim(inventoryPath, "/"), "/")
+	if len(segments) < 3 || segments[1] != "vm" {
+		return "", "", "", errors.Errorf("%v is not a valid vm inventory path, expected format is /<datacenter>/vm/<folders>/<vm name>", inventoryPath)
+	}
+
+	flavour := c.APIFlavour()
+
+	filters := url.Values{}
+	filters.Set(flavour.filterParam("names"), segments[0])
+
+	var datacenterList []DatacenterSummary
+	if err := c.list(ctx, "/vcenter/datacenter", filters, "datacenter lookup", &datacenterList); err != nil {
+		return "", "", "", err
+	}
+	if len(datacenterList) != 1 {
+		return "", "", "", errors.Errorf("unable to find a unique %v datacenter, found %v datacenters", segments[0], len(datacenterList))
+	}
+	datacenter := datacenterList[0].MsgDatacenter
+
+	// the vm root folder of the datacenter is named as vm
+	// the remaining folders are looked up under their parent folder
+	var parentFolder string
+	for _, folderName := range segments[1 : len(segments)-1] {
+
+		filters := url.Values{}
+		filters.Set(flavour.filterParam("names"), folderName)
+		filters.Set(flavour.filterParam("type"), "VIRTUAL_MACHINE")
+		filters.Set(flavour.filterParam("datacenters"), datacenter)
+		if parentFolder != "" {
+			filters.Set(flavour.filterParam("parent_folders"), parentFolder)
+		}
+
+		var folderList []FolderSummary
+		if err := c.list(ctx, "/vcenter/folder", filters, "folder lookup", &folderList); err != nil {
+			return "", "", "", err
+		}
+		if len(folderList) != 1 {
+			return "", "", "", errors.Errorf("unable to find a unique %v folder in %v path, found %v folders", folderName, inventoryPath, len(folderList))
+		}
+		parentFolder = folderList[0].MsgFolder
+	}
+
+	return datacenter, parentFolder, segments[len(segments)-1], nil
+}
+
+// list fetches the resources matching the given filters and decodes them into the given list
+func (c *VcenterClient) list(ctx context.Context, path string, filters url.Values, action string, list interface{}) error {
+
+	if len(filters) != 0 {
+		path = path + "?" + filters.Encode()
+	}
+
+	body, err := c.do(ctx, "GET", path, nil, action)
+	if err != nil {
+		return err
+	}
+
+	return c.decodeValue(body, list)
+}
@@ -0,0 +1,163 @@
+package vmware
+
+import (
+	"context"
+	"strings"
+
+	"github.com/litmuschaos/litmus-go/pkg/log"
+	"github.com/pkg/errors"
+	"github.com/sirupsen/logrus"
+)
+
+const (
+	// PowerStateOn is the power state of a powered on vm
+	PowerStateOn = "POWERED_ON"
+	// PowerStateOff is the power state of a powered off vm
+	PowerStateOff = "POWERED_OFF"
+	// PowerStateSuspended is the power state of a suspended vm
+	PowerStateSuspended = "SUSPENDED"
+
+	// ToolsRunStateRunning is the run state of the vmware tools running inside the guest os
+	ToolsRunStateRunning = "RUNNING"
+)
+
+// VMPowerOff powers off the given vm
+func (c *VcenterClient) VMPowerOff(ctx context.Context, appVMMoid string) error {
+	return c.powerAction(ctx, appVMMoid, "stop", "vm power off")
+}
+
+// VMPowerOn powers on the given vm
+func (c *VcenterClient) VMPowerOn(ctx context.Context, appVMMoid string) error {
+	return c.powerAction(ctx, appVMMoid, "start", "vm power on")
+}
+
+// VMSuspend suspends the given vm, the memory state of the vm is preserved
+func (c *VcenterClient) VMSuspend(ctx context.Context, appVMMoid string) error {
+	return c.powerAction(ctx, appVMMoid, "suspend", "vm suspend")
+}
+
+// VMResume resumes the given suspended vm from its preserved memory state
+func (c *VcenterClient) VMResume(ctx context.Context, appVMMoid string) error {
+	return c.powerAction(ctx, appVMMoid, "start", "vm resume")
+}
+
+// powerAction invokes the given power action on the vm
+func (c *VcenterClient) powerAction(ctx context.Context, appVMMoid, action, description string) error {
+
+	if _, err := c.do(ctx, "POST", c.APIFlavour().actionPath("/vcenter/vm/"+appVMMoid+"/power", action), nil, description); err != nil {
+		return err
+	}
+
+	log.InfoWithValues("[Info]: Invoked the vm power action", logrus.Fields{
+		"VM ID":  appVMMoid,
+		"Action": action,
+	})
+
+	return nil
+}
+
+// GetVMPowerState returns the power state of the given vm, e.g, POWERED_ON
+func (c *VcenterClient) GetVMPowerState(ctx context.Context, appVMMoid string) (string, error) {
+
+	type PowerInfo struct {
+		MsgState string `json:"state"`
+	}
+
+	body, err := c.do(ctx, "GET", "/vcenter/vm/"+appVMMoid+"/power", nil, "vm power state fetch")
+	if err != nil {
+		return "", err
+	}
+
+	var powerInfo PowerInfo
+	if err = c.decodeValue(body, &powerInfo); err != nil {
+		return "", err
+	}
+
+	return powerInfo.MsgState, nil
+}
+
+// GetToolsRunState returns the run state of the vmware tools inside the given vm, e.g, RUNNING
+func (c *VcenterClient) GetToolsRunState(ctx context.Context, appVMMoid string) (string, error) {
+
+	type ToolsInfo struct {
+		MsgRunState string `json:"run_state"`
+	}
+
+	body, err := c.do(ctx, "GET", "/vcenter/vm/"+appVMMoid+"/tools", nil, "vm tools state fetch")
+	if err != nil {
+		return "", err
+	}
+
+	var toolsInfo ToolsInfo
+	if err = c.decodeValue(body, &toolsInfo); err != nil {
+		return "", err
+	}
+
+	return toolsInfo.MsgRunState, nil
+}
+
+// WaitForVMPowerState waits for the given vm to get in the given power state
+func (c *VcenterClient) WaitForVMPowerState(ctx context.Context, appVMMoid, powerState string, delay, timeout int) error {
+
+	log.Infof("[Status]: Checking %v vm for %v state", appVMMoid, powerState)
+	return PollUntil(ctx, delay, timeout, func(attempt uint) error {
+
+		vmPowerState, err := c.GetVMPowerState(ctx, appVMMoid)
+		if err != nil {
+			return errors.Errorf("failed to get the vm power state, err: %v", err)
+		}
+
+		log.Infof("[Info]: The vm power state is %v", vmPowerState)
+		if vmPowerState != powerState {
+			return errors.Errorf("vm is not yet in %v state", powerState)
+		}
+
+		return nil
+	})
+}
+
+// WaitForToolsRunning waits for the vmware tools inside the given vm to report running
+func (c *VcenterClient) WaitForToolsRunning(ctx context.Context, appVMMoid string, delay, timeout int) error {
+
+	log.Infof("[Status]: Checking the vmware tools state of %v vm", appVMMoid)
+	return PollUntil(ctx, delay, timeout, func(attempt uint) error {
+
+		toolsRunState, err := c.GetToolsRunState(ctx, appVMMoid)
+		if err != nil {
+			return errors.Errorf("failed to get the vmware tools state, err: %v", err)
+		}
+
+		log.Infof("[Info]: The vmware tools state is %v", toolsRunState)
+		if toolsRunState != ToolsRunStateRunning {
+			return errors.Errorf("vmware tools are not yet running")
+		}
+
+		return nil
+	})
+}
+
+// VMPowerStateCheck verifies that all the given vms are powered on
+func (c *VcenterClient) VMPowerStateCheck(ctx context.Context, appVMMoids string) error {
+
+	if c.server == "" {
+		return errors.Errorf("no vcenter server provided, please provide the server url")
+	}
+
+	if appVMMoids == "" {
+		return errors.Errorf("no vm id provided, please provide vm id")
+	}
+
+	for _, appVMMoid := range strings.Split(appVMMoids, ",") {
+
+		vmPowerState, err := c.GetVMPowerState(ctx, appVMMoid)
+		if err != nil {
+			return errors.Errorf("failed to get the power state of %v vm, err: %v", appVMMoid, err)
+		}
+
+		if vmPowerState != PowerStateOn {
+			return errors.Errorf("%v vm is not powered on, vm is in %v state", appVMMoid, vmPowerState)
+		}
+	}
+
+	return nil
+}
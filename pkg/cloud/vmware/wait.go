@@ -0,0 +1,60 @@
+package vmware
+
+import (
+	"context"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+// Condition defines the prototype of the condition checked by PollUntil
+type Condition func(attempt uint) error
+
+// PollUntil checks the condition after every delay (in sec) until it succeeds or the timeout (in sec) elapses
+// it returns early with the context error if the context is cancelled
+func PollUntil(ctx context.Context, delay, timeout int, condition Condition) error {
+
+	if condition == nil {
+		return errors.Errorf("no condition specified")
+	}
+
+	if delay <= 0 {
+		delay = 1
+	}
+
+	lastAttempt := uint(timeout / delay)
+	for attempt := uint(0); ; attempt++ {
+
+		if ctxErr := ctx.Err(); ctxErr != nil {
+			return ctxErr
+		}
+
+		err := condition(attempt)
+		if err == nil {
+			return nil
+		}
+
+		// the timeout has elapsed, return without waiting for another delay
+		if attempt >= lastAttempt {
+			return err
+		}
+
+		if waitErr := WaitForDuration(ctx, delay); waitErr != nil {
+			return waitErr
+		}
+	}
+}
+
+// WaitForDuration waits for the given time duration (in sec) or until the context is cancelled
+func WaitForDuration(ctx context.Context, duration int) error {
+
+	timer := time.NewTimer(time.Duration(duration) * time.Second)
+	defer timer.Stop()
+
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
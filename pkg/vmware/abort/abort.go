@@ -0,0 +1,163 @@
+package abort
+
+import (
+	"context"
+	"os"
+	"os/signal"
+	"sync"
+	"syscall"
+
+	"github.com/chaosnative/litmus-go/pkg/cloud/vmware"
+	"github.com/litmuschaos/litmus-go/pkg/clients"
+	"github.com/litmuschaos/litmus-go/pkg/events"
+	"github.com/litmuschaos/litmus-go/pkg/log"
+	"github.com/litmuschaos/litmus-go/pkg/result"
+	"github.com/litmuschaos/litmus-go/pkg/types"
+)
+
+// RevertFunc reverts the injected chaos when an abort signal is received
+type RevertFunc func(ctx context.Context)
+
+// Watcher is the single abort path of a vmware experiment
+// on an abort signal, it cancels the chaos injection, reverts the chaos, records the abort in the chaos result,
+// releases the vcenter session and then exits
+type Watcher struct {
+	experimentName string
+	clients        clients.ClientSets
+	resultDetails  *types.ResultDetails
+	chaosDetails   *types.ChaosDetails
+	eventsDetails  *types.EventDetails
+	signals        chan os.Signal
+
+	// done is closed once the abort completes
+	done chan struct{}
+
+	// mu guards the registered revert against the abort, the revert is not changed once the abort starts
+	mu            sync.Mutex
+	aborting      bool
+	vcenterClient *vmware.VcenterClient
+	cancel        context.CancelFunc
+	revert        RevertFunc
+}
+
+// NewWatcher returns a new abort watcher for the given experiment
+func NewWatcher(experimentName string, clients clients.ClientSets, resultDetails *types.ResultDetails, chaosDetails *types.ChaosDetails, eventsDetails *types.EventDetails) *Watcher {
+	return &Watcher{
+		experimentName: experimentName,
+		clients:        clients,
+		resultDetails:  resultDetails,
+		chaosDetails:   chaosDetails,
+		eventsDetails:  eventsDetails,
+		done:           make(chan struct{}),
+	}
+}
+
+// Start starts watching for the abort signal in a separate go routine
+func (w *Watcher) Start() {
+
+	// signals channel is used to transmit signal notifications.
+	w.signals = make(chan os.Signal, 1)
+	// Catch and relay certain signal(s) to signals channel.
+	signal.Notify(w.signals, os.Interrupt, syscall.SIGTERM)
+
+	go w.watch()
+}
+
+// SetVcenterClient sets the vcenter client whose session is released on abort
+func (w *Watcher) SetVcenterClient(vcenterClient *vmware.VcenterClient) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	w.vcenterClient = vcenterClient
+}
+
+// Arm registers the cancel func of the chaos injection context and the revert of the injected chaos
+// the caller is blocked until the watcher exits, if the abort is already in progress
+func (w *Watcher) Arm(cancel context.CancelFunc, revert RevertFunc) {
+	w.mu.Lock()
+	aborting := w.aborting
+	if !aborting {
+		w.cancel = cancel
+		w.revert = revert
+	}
+	w.mu.Unlock()
+
+	if aborting {
+		w.Wait()
+	}
+}
+
+// Disarm removes the registered revert, once the chaos injection completes
+// the caller is blocked until the watcher exits, if the abort is already in progress,
+// so that the experiment does not proceed while the registered revert is running
+func (w *Watcher) Disarm() {
+	w.mu.Lock()
+	aborting := w.aborting
+	if !aborting {
+		w.cancel = nil
+		w.revert = nil
+	}
+	w.mu.Unlock()
+
+	if aborting {
+		w.Wait()
+	}
+}
+
+// watch waits for the abort signal and aborts the experiment
+func (w *Watcher) watch() {
+
+	// waiting until the abort signal received
+	<-w.signals
+
+	w.mu.Lock()
+	w.aborting = true
+	vcenterClient, cancel, revert := w.vcenterClient, w.cancel, w.revert
+	w.mu.Unlock()
+
+	log.Info("[Chaos]: Chaos Experiment Abortion started because of terminated signal received")
+
+	// revert context is independent of the cancelled chaos injection context
+	ctx := context.Background()
+
+	if cancel != nil {
+		cancel()
+	}
+
+	if revert != nil {
+		log.Info("[Abort]: Chaos Revert Started")
+		revert(ctx)
+		log.Info("[Abort]: Chaos Revert Completed")
+	}
+
+	// updating the chaosresult after stopped
+	failStep := "Chaos injection stopped!"
+	types.SetResultAfterCompletion(w.resultDetails, "Stopped", "Stopped", failStep)
+	if err := result.ChaosResult(w.chaosDetails, w.clients, w.resultDetails, "EOT"); err != nil {
+		log.Errorf("Unable to Update the Chaos Result when an abort signal is received, err: %v", err)
+	}
+
+	// generating summary event in chaosengine
+	msg := w.experimentName + " experiment has been aborted"
+	types.SetEngineEventAttributes(w.eventsDetails, types.Summary, msg, "Warning", w.chaosDetails)
+	events.GenerateEvents(w.eventsDetails, w.clients, w.chaosDetails, "ChaosEngine")
+
+	// generating summary event in chaosresult
+	types.SetResultEventAttributes(w.eventsDetails, types.AbortVerdict, msg, "Warning", w.resultDetails)
+	events.GenerateEvents(w.eventsDetails, w.clients, w.chaosDetails, "ChaosResult")
+
+	if vcenterClient != nil {
+		if err := vcenterClient.Logout(ctx); err != nil {
+			log.Errorf("failed to logout from the vcenter session when an abort signal is received, err: %v", err)
+		}
+	}
+
+	close(w.done)
+	os.Exit(1)
+}
+
+// Wait blocks the caller until the abort completes and then exits, it is used once the chaos injection is interrupted by an abort signal
+func (w *Watcher) Wait() {
+	log.Info("[Abort]: Chaos injection interrupted, waiting for the chaos revert")
+	<-w.done
+	os.Exit(1)
+}
@@ -0,0 +1,74 @@
+package environment
+
+import (
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/chaosnative/litmus-go/pkg/cloud/vmware"
+	"github.com/litmuschaos/litmus-go/pkg/types"
+)
+
+//VcenterDetails contains the vcenter connection details shared by the vmware experiments
+type VcenterDetails struct {
+	VcenterServer    string
+	VcenterUser      string
+	VcenterPass      string
+	VcenterCredsPath string
+	VcenterTLS       vmware.TLSDetails
+	VcenterRetry     vmware.RetryPolicy
+	VcenterAPI       string
+}
+
+//GetVcenterENV fetches the vcenter connection env variables from the runner pod
+func GetVcenterENV(vcenterDetails *VcenterDetails) {
+	vcenterDetails.VcenterServer = types.Getenv("VCENTERSERVER", "")
+	vcenterDetails.VcenterUser = types.Getenv("VCENTERUSER", "")
+	vcenterDetails.VcenterPass = types.Getenv("VCENTERPASS", "")
+	vcenterDetails.VcenterCredsPath = types.Getenv("VCENTER_CREDENTIALS_PATH", "")
+	vcenterDetails.VcenterTLS.CACertPath = types.Getenv("VCENTER_CA_CERT_PATH", "")
+	vcenterDetails.VcenterTLS.CACert = types.Getenv("VCENTER_CA_CERT", "")
+	vcenterDetails.VcenterTLS.ServerName = types.Getenv("VCENTER_TLS_SERVER_NAME", "")
+	vcenterDetails.VcenterTLS.InsecureSkipVerify, _ = strconv.ParseBool(types.Getenv("VCENTER_INSECURE_SKIP_VERIFY", "false"))
+	vcenterDetails.VcenterRetry = GetRetryPolicy()
+	vcenterDetails.VcenterAPI = types.Getenv("VCENTER_API_FLAVOUR", "auto")
+}
+
+//GetCredentialsProvider returns the provider for the vcenter credentials
+//the credentials inside the mounted secret directory are preferred over the env variables
+//and are read again for every login, so that the rotated credentials are picked up
+func GetCredentialsProvider(vcenterDetails *VcenterDetails) vmware.CredentialsProvider {
+	if vcenterDetails.VcenterCredsPath != "" {
+		return vmware.FileCredentials(vcenterDetails.VcenterCredsPath)
+	}
+	return vmware.StaticCredentials(vcenterDetails.VcenterUser, vcenterDetails.VcenterPass)
+}
+
+//GetRetryPolicy derives the retry policy for the vcenter api calls from the VCENTER_RETRY_* env variables
+//the default retry policy is used for the env variables which are not provided
+func GetRetryPolicy() vmware.RetryPolicy {
+	retryPolicy := vmware.DefaultRetryPolicy()
+
+	if attempts, err := strconv.Atoi(types.Getenv("VCENTER_RETRY_ATTEMPTS", "")); err == nil && attempts > 0 {
+		retryPolicy.Attempts = attempts
+	}
+	if baseDelay, err := strconv.Atoi(types.Getenv("VCENTER_RETRY_BASE_DELAY", "")); err == nil && baseDelay >= 0 {
+		retryPolicy.BaseDelay = time.Duration(baseDelay) * time.Second
+	}
+	if maxDelay, err := strconv.Atoi(types.Getenv("VCENTER_RETRY_MAX_DELAY", "")); err == nil && maxDelay > 0 {
+		retryPolicy.MaxDelay = time.Duration(maxDelay) * time.Second
+	}
+	if jitter, err := strconv.Atoi(types.Getenv("VCENTER_RETRY_JITTER_PERCENTAGE", "")); err == nil && jitter >= 0 && jitter <= 100 {
+		retryPolicy.JitterPercentage = jitter
+	}
+	if statusCodes := types.Getenv("VCENTER_RETRY_STATUS_CODES", ""); statusCodes != "" {
+		retryPolicy.RetryableStatusCodes = nil
+		for _, statusCode := range strings.Split(statusCodes, ",") {
+			if code, err := strconv.Atoi(strings.TrimSpace(statusCode)); err == nil {
+				retryPolicy.RetryableStatusCodes = append(retryPolicy.RetryableStatusCodes, code)
+			}
+		}
+	}
+
+	return retryPolicy
+}
@@ -0,0 +1,221 @@
+package recovery
+
+import (
+	"encoding/json"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/litmuschaos/litmus-go/pkg/clients"
+	"github.com/pkg/errors"
+	apiv1 "k8s.io/api/core/v1"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/util/retry"
+)
+
+// storeLabel is the label of the recovery configmaps, it is used to list the recovery configmaps of all the experiments
+const storeLabel = "app.kubernetes.io/component=vmware-disk-recovery"
+
+// DetachedDisk contains the details required to reattach a detached disk
+type DetachedDisk struct {
+	VMMoid         string    `json:"vmMoid"`
+	DiskId         string    `json:"diskId"`
+	DiskPath       string    `json:"diskPath"`
+	ControllerType string    `json:"controllerType,omitempty"`
+	Bus            int       `json:"bus"`
+	Unit           int       `json:"unit"`
+	DetachTime     time.Time `json:"detachTime"`
+}
+
+// Store persists the detached disks inside a configmap,
+// so that they can be reattached if the experiment pod is restarted before the chaos is reverted
+type Store struct {
+	clients   clients.ClientSets
+	namespace string
+	name      string
+	mu        sync.Mutex
+}
+
+// StoreName returns the name of the recovery configmap of the given experiment
+func StoreName(engineName, experimentName string) string {
+	name := experimentName + "-recovery"
+	if engineName != "" {
+		name = engineName + "-" + name
+	}
+	return name
+}
+
+// NewStore returns the recovery store persisted in the given configmap
+func NewStore(clients clients.ClientSets, namespace, name string) *Store {
+	return &Store{
+		clients:   clients,
+		namespace: namespace,
+		name:      name,
+	}
+}
+
+// ListStoreNames returns the names of the recovery configmaps in the given namespace
+func ListStoreNames(clients clients.ClientSets, namespace string) ([]string, error) {
+
+	configMapList, err := clients.KubeClient.CoreV1().ConfigMaps(namespace).List(v1.ListOptions{LabelSelector: storeLabel})
+	if err != nil {
+		return nil, errors.Errorf("unable to list the recovery configmaps, err: %v", err)
+	}
+
+	var nameList []string
+	for _, configMap := range configMapList.Items {
+		nameList = append(nameList, configMap.Name)
+	}
+	sort.Strings(nameList)
+	return nameList, nil
+}
+
+// Name returns the name of the recovery configmap
+func (store *Store) Name() string {
+	return store.name
+}
+
+// Record stores the details of the disk, it should be called before detaching the disk
+// the configmap is updated with the optimistic concurrency and retried on conflict,
+// as the stores of the parallel experiments or the experiment and its revert may update it concurrently
+func (store *Store) Record(disk DetachedDisk) error {
+
+	store.mu.Lock()
+	defer store.mu.Unlock()
+
+	value, err := json.Marshal(disk)
+	if err != nil {
+		return err
+	}
+
+	if err = retry.RetryOnConflict(retry.DefaultRetry, func() error {
+		return store.record(recoveryKey(disk.VMMoid, disk.DiskId), string(value))
+	}); err != nil {
+		return errors.Errorf("unable to record %v disk in the %v recovery configmap, err: %v", disk.DiskId, store.name, err)
+	}
+	return nil
+}
+
+// record adds the given record to the configmap, the configmap is created if it does not exist
+func (store *Store) record(key, value string) error {
+
+	configMap, err := store.clients.KubeClient.CoreV1().ConfigMaps(store.namespace).Get(store.name, v1.GetOptions{})
+	if err != nil {
+		if !k8serrors.IsNotFound(err) {
+			return err
+		}
+
+		configMap = &apiv1.ConfigMap{
+			ObjectMeta: v1.ObjectMeta{
+				Name:      store.name,
+				Namespace: store.namespace,
+				Labels: map[string]string{
+					"app.kubernetes.io/part-of":   "litmus",
+					"app.kubernetes.io/component": "vmware-disk-recovery",
+				},
+			},
+			Data: map[string]string{
+				key: value,
+			},
+		}
+
+		_, err = store.clients.KubeClient.CoreV1().ConfigMaps(store.namespace).Create(configMap)
+		if k8serrors.IsAlreadyExists(err) {
+			// the configmap is created concurrently, the record is added to it by the next attempt
+			return k8serrors.NewConflict(apiv1.Resource("configmaps"), store.name, err)
+		}
+		return err
+	}
+
+	if configMap.Data == nil {
+		configMap.Data = map[string]string{}
+	}
+	configMap.Data[key] = value
+
+	_, err = store.clients.KubeClient.CoreV1().ConfigMaps(store.namespace).Update(configMap)
+	return err
+}
+
+// Remove deletes the details of the disk, it should be called once the disk is reattached
+// the configmap is deleted once it does not contain any detached disk
+func (store *Store) Remove(vmMoid, diskId string) error {
+
+	store.mu.Lock()
+	defer store.mu.Unlock()
+
+	if err := retry.RetryOnConflict(retry.DefaultRetry, func() error {
+		return store.remove(recoveryKey(vmMoid, diskId))
+	}); err != nil {
+		return errors.Errorf("unable to remove %v disk from the %v recovery configmap, err: %v", diskId, store.name, err)
+	}
+	return nil
+}
+
+// remove deletes the given record from the configmap
+// the empty configmap is deleted only if it is not updated since it was read
+func (store *Store) remove(key string) error {
+
+	configMap, err := store.clients.KubeClient.CoreV1().ConfigMaps(store.namespace).Get(store.name, v1.GetOptions{})
+	if err != nil {
+		if k8serrors.IsNotFound(err) {
+			return nil
+		}
+		return err
+	}
+
+	if _, ok := configMap.Data[key]; !ok {
+		return nil
+	}
+	delete(configMap.Data, key)
+
+	if len(configMap.Data) == 0 {
+		err = store.clients.KubeClient.CoreV1().ConfigMaps(store.namespace).Delete(store.name, &v1.DeleteOptions{
+			Preconditions: &v1.Preconditions{ResourceVersion: &configMap.ResourceVersion},
+		})
+		if k8serrors.IsNotFound(err) {
+			return nil
+		}
+		return err
+	}
+
+	_, err = store.clients.KubeClient.CoreV1().ConfigMaps(store.namespace).Update(configMap)
+	return err
+}
+
+// List returns the details of all the outstanding detached disks
+func (store *Store) List() ([]DetachedDisk, error) {
+
+	store.mu.Lock()
+	defer store.mu.Unlock()
+
+	configMap, err := store.clients.KubeClient.CoreV1().ConfigMaps(store.namespace).Get(store.name, v1.GetOptions{})
+	if err != nil {
+		if k8serrors.IsNotFound(err) {
+			return nil, nil
+		}
+		return nil, errors.Errorf("unable to get the %v recovery configmap, err: %v", store.name, err)
+	}
+
+	var diskList []DetachedDisk
+	for key, value := range configMap.Data {
+
+		var disk DetachedDisk
+		if err := json.Unmarshal([]byte(value), &disk); err != nil {
+			return nil, errors.Errorf("unable to parse the %v recovery record, err: %v", key, err)
+		}
+
+		diskList = append(diskList, disk)
+	}
+
+	sort.Slice(diskList, func(i, j int) bool {
+		return diskList[i].DetachTime.Before(diskList[j].DetachTime)
+	})
+
+	return diskList, nil
+}
+
+// recoveryKey returns the configmap key for the given disk
+func recoveryKey(vmMoid, diskId string) string {
+	return vmMoid + "." + diskId
+}
@@ -0,0 +1,120 @@
+package recovery
+
+import (
+	"context"
+
+	"github.com/chaosnative/litmus-go/pkg/cloud/vmware"
+	"github.com/litmuschaos/litmus-go/pkg/log"
+	"github.com/pkg/errors"
+	"github.com/sirupsen/logrus"
+)
+
+// RevertReport contains the outcome of reverting the recorded disks
+type RevertReport struct {
+	Reattached      []DetachedDisk
+	AlreadyAttached []DetachedDisk
+	Failed          []DetachedDisk
+}
+
+// RevertDetachedDisks reattaches the recorded disks of the given vms (or of all the vms, if none are given)
+// the records of the reattached disks are removed, whereas the records of the failed disks are retained for the next attempt
+func RevertDetachedDisks(ctx context.Context, appVMMoidList []string, store *Store, vcenterClient *vmware.VcenterClient, delay, timeout int) (RevertReport, error) {
+
+	var report RevertReport
+
+	diskList, err := store.List()
+	if err != nil {
+		return report, err
+	}
+
+	if len(diskList) == 0 {
+		return report, nil
+	}
+
+	log.Infof("[Recovery]: Found %v disks detached by a previous run", len(diskList))
+
+	targetVMs := map[string]bool{}
+	for _, appVMMoid := range appVMMoidList {
+		targetVMs[appVMMoid] = true
+	}
+
+	for _, disk := range diskList {
+
+		if len(targetVMs) != 0 && !targetVMs[disk.VMMoid] {
+			log.Infof("[Skip]: %v vm is not a target, skipping %v disk", disk.VMMoid, disk.DiskId)
+			continue
+		}
+
+		reattached, err := reattachDisk(ctx, disk, vcenterClient, delay, timeout)
+		if err != nil {
+			log.Errorf("[Recovery]: Failed to reattach %v disk to %v vm, err: %v", disk.DiskId, disk.VMMoid, err)
+			report.Failed = append(report.Failed, disk)
+			continue
+		}
+
+		if err := store.Remove(disk.VMMoid, disk.DiskId); err != nil {
+			return report, err
+		}
+
+		if reattached {
+			report.Reattached = append(report.Reattached, disk)
+		} else {
+			report.AlreadyAttached = append(report.AlreadyAttached, disk)
+		}
+	}
+
+	return report, nil
+}
+
+// reattachDisk attaches the vmdk file of the detached disk back to the vm, if it is not already attached
+// it returns true if the disk was reattached
+func reattachDisk(ctx context.Context, disk DetachedDisk, vcenterClient *vmware.VcenterClient, delay, timeout int) (bool, error) {
+
+	diskId, err := vcenterClient.GetDiskIdByPath(ctx, disk.VMMoid, disk.DiskPath)
+	if err != nil {
+		return false, err
+	}
+
+	if diskId != "" {
+		log.Infof("[Skip]: %v disk is already attached to %v vm", disk.DiskPath, disk.VMMoid)
+		return false, nil
+	}
+
+	log.InfoWithValues("[Recovery]: Attaching the disk detached by a previous run", logrus.Fields{
+		"VM ID":       disk.VMMoid,
+		"Disk ID":     disk.DiskId,
+		"Disk Path":   disk.DiskPath,
+		"Detach Time": disk.DetachTime,
+	})
+
+	//the disk is pinned to its original slot, if the slot was recorded
+	if disk.ControllerType != "" {
+		_, err = vcenterClient.DiskAttachAt(ctx, disk.VMMoid, vmware.DiskInfo{
+			Disk:     disk.DiskId,
+			Type:     disk.ControllerType,
+			Bus:      disk.Bus,
+			Unit:     disk.Unit,
+			VMDKFile: disk.DiskPath,
+		})
+	} else {
+		_, err = vcenterClient.DiskAttach(ctx, disk.VMMoid, disk.DiskPath)
+	}
+	if err != nil {
+		return false, err
+	}
+
+	err = vmware.PollUntil(ctx, delay, timeout, func(attempt uint) error {
+
+		diskId, err := vcenterClient.GetDiskIdByPath(ctx, disk.VMMoid, disk.DiskPath)
+		if err != nil {
+			return err
+		}
+
+		if diskId == "" {
+			return errors.Errorf("%v disk is not yet attached", disk.DiskPath)
+		}
+		return nil
+	})
+
+	return err == nil, err
+}
@@ -0,0 +1,54 @@
+package vcenter
+
+import (
+	"context"
+
+	"github.com/chaosnative/litmus-go/pkg/cloud/vmware"
+	"github.com/chaosnative/litmus-go/pkg/vmware/abort"
+	vmwareEnv "github.com/chaosnative/litmus-go/pkg/vmware/environment"
+	"github.com/litmuschaos/litmus-go/pkg/log"
+	"github.com/pkg/errors"
+)
+
+//Connect builds the vcenter client from the vcenter details and logs in to the vcenter server
+//the vcenter session is released by the abort watcher, if any, when an abort signal is received
+func Connect(ctx context.Context, vcenterDetails *vmwareEnv.VcenterDetails, abortWatcher *abort.Watcher) (*vmware.VcenterClient, error) {
+
+	// BUILD THE TLS CONFIG FOR THE VCENTER CONNECTION
+	tlsConfig, err := vmware.NewTLSConfig(vcenterDetails.VcenterTLS)
+	if err != nil {
+		return nil, errors.Errorf("unable to build the vcenter tls config, err: %v", err)
+	}
+
+	if vcenterDetails.VcenterTLS.InsecureSkipVerify {
+		log.Warn("[Warning]: Vcenter TLS certificate verification is disabled")
+	}
+
+	// GET THE VCENTER API FLAVOUR
+	apiFlavour, err := vmware.ParseAPIFlavour(vcenterDetails.VcenterAPI)
+	if err != nil {
+		return nil, errors.Errorf("unable to parse the vcenter api flavour, err: %v", err)
+	}
+
+	// GET SESSION ID TO LOGIN TO VCENTER
+	vcenterClient := vmware.NewVcenterClient(vcenterDetails.VcenterServer,
+		vmware.WithCredentialsProvider(vmwareEnv.GetCredentialsProvider(vcenterDetails)),
+		vmware.WithTLSConfig(tlsConfig),
+		vmware.WithRetryPolicy(vcenterDetails.VcenterRetry),
+		vmware.WithAPIFlavour(apiFlavour))
+	if abortWatcher != nil {
+		abortWatcher.SetVcenterClient(vcenterClient)
+	}
+	if err = vcenterClient.Login(ctx); err != nil {
+		return nil, errors.Errorf("unable to get vcenter session id, err: %v", err)
+	}
+
+	return vcenterClient, nil
+}
+
+//Logout deletes the vcenter session, it is deferred by the experiments once connected
+func Logout(vcenterClient *vmware.VcenterClient) {
+	if err := vcenterClient.Logout(context.Background()); err != nil {
+		log.Errorf("Vcenter Logout failed, err: %v", err)
+	}
+}
@@ -0,0 +1,44 @@
+package environment
+
+import (
+	"strconv"
+	"strings"
+
+	clientTypes "k8s.io/apimachinery/pkg/types"
+
+	vmwareEnv "github.com/chaosnative/litmus-go/pkg/vmware/environment"
+	experimentTypes "github.com/chaosnative/litmus-go/pkg/vmware/vmware-device-chaos/types"
+	"github.com/litmuschaos/litmus-go/pkg/types"
+)
+
+//GetENV fetches all the env variables from the runner pod
+//the experiment name and the default device type are provided by the experiment sharing the device chaos lib
+func GetENV(experimentDetails *experimentTypes.ExperimentDetails, experimentName, deviceType string) {
+	experimentDetails.ExperimentName = types.Getenv("EXPERIMENT_NAME", experimentName)
+	experimentDetails.ChaosNamespace = types.Getenv("CHAOS_NAMESPACE", "litmus")
+	experimentDetails.EngineName = types.Getenv("CHAOSENGINE", "")
+	experimentDetails.ChaosDuration, _ = strconv.Atoi(types.Getenv("TOTAL_CHAOS_DURATION", "30"))
+	experimentDetails.ChaosInterval, _ = strconv.Atoi(types.Getenv("CHAOS_INTERVAL", "30"))
+	experimentDetails.RampTime, _ = strconv.Atoi(types.Getenv("RAMP_TIME", ""))
+	experimentDetails.ChaosLib = types.Getenv("LIB", "litmus")
+	experimentDetails.AppNS = types.Getenv("APP_NAMESPACE", "")
+	experimentDetails.AppLabel = types.Getenv("APP_LABEL", "")
+	experimentDetails.AppKind = types.Getenv("APP_KIND", "")
+	experimentDetails.ChaosUID = clientTypes.UID(types.Getenv("CHAOS_UID", ""))
+	experimentDetails.InstanceID = types.Getenv("INSTANCE_ID", "")
+	experimentDetails.ChaosPodName = types.Getenv("POD_NAME", "")
+	experimentDetails.AuxiliaryAppInfo = types.Getenv("AUXILIARY_APPINFO", "")
+	experimentDetails.TargetContainer = types.Getenv("TARGET_CONTAINER", "")
+	experimentDetails.Delay, _ = strconv.Atoi(types.Getenv("STATUS_CHECK_DELAY", "2"))
+	experimentDetails.Timeout, _ = strconv.Atoi(types.Getenv("STATUS_CHECK_TIMEOUT", "180"))
+	experimentDetails.Sequence = types.Getenv("SEQUENCE", "parallel")
+	experimentDetails.AppVMMoids = types.Getenv("APP_VM_MOIDS", "")
+	experimentDetails.AppVMNames = types.Getenv("APP_VM_NAMES", "")
+	experimentDetails.DeviceType = strings.ToLower(types.Getenv("DEVICE_TYPE", deviceType))
+	experimentDetails.DeviceIds = types.Getenv("VIRTUAL_DEVICE_IDS", "")
+	experimentDetails.DeviceSelectors = types.Getenv("VIRTUAL_DEVICE_SELECTORS", "")
+	experimentDetails.DeviceAffectedPerc, _ = strconv.Atoi(types.Getenv("DEVICE_AFFECTED_PERC", "100"))
+	experimentDetails.RandomSeed, _ = strconv.ParseInt(types.Getenv("RANDOM_SEED", "0"), 10, 64)
+	experimentDetails.AllowBootDetach, _ = strconv.ParseBool(types.Getenv("ALLOW_BOOT_DISK_DETACH", "false"))
+	vmwareEnv.GetVcenterENV(&experimentDetails.VcenterDetails)
+}
@@ -0,0 +1,28 @@
+package environment
+
+import (
+	"strconv"
+
+	clientTypes "k8s.io/apimachinery/pkg/types"
+
+	vmwareEnv "github.com/chaosnative/litmus-go/pkg/vmware/environment"
+	experimentTypes "github.com/chaosnative/litmus-go/pkg/vmware/vmware-disk-loss-revert/types"
+	"github.com/litmuschaos/litmus-go/pkg/types"
+)
+
+//GetENV fetches all the env variables from the runner pod
+func GetENV(experimentDetails *experimentTypes.ExperimentDetails) {
+	experimentDetails.ExperimentName = types.Getenv("EXPERIMENT_NAME", "vmware-disk-loss-revert")
+	experimentDetails.ChaosNamespace = types.Getenv("CHAOS_NAMESPACE", "litmus")
+	experimentDetails.EngineName = types.Getenv("CHAOSENGINE", "")
+	experimentDetails.ChaosUID = clientTypes.UID(types.Getenv("CHAOS_UID", ""))
+	experimentDetails.InstanceID = types.Getenv("INSTANCE_ID", "")
+	experimentDetails.ChaosPodName = types.Getenv("POD_NAME", "")
+	experimentDetails.Delay, _ = strconv.Atoi(types.Getenv("STATUS_CHECK_DELAY", "2"))
+	experimentDetails.Timeout, _ = strconv.Atoi(types.Getenv("STATUS_CHECK_TIMEOUT", "180"))
+	experimentDetails.AppVMMoids = types.Getenv("APP_VM_MOIDS", "")
+	experimentDetails.AppVMNames = types.Getenv("APP_VM_NAMES", "")
+	experimentDetails.VMTags = types.Getenv("VM_TAGS", "")
+	experimentDetails.RecoveryConfigMaps = types.Getenv("RECOVERY_CONFIGMAPS", "")
+	vmwareEnv.GetVcenterENV(&experimentDetails.VcenterDetails)
+}
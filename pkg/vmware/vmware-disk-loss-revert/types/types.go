@@ -0,0 +1,23 @@
+package types
+
+import (
+	vmwareEnv "github.com/chaosnative/litmus-go/pkg/vmware/environment"
+	clientTypes "k8s.io/apimachinery/pkg/types"
+)
+
+// ExperimentDetails is for collecting all the experiment-related details
+type ExperimentDetails struct {
+	ExperimentName     string
+	EngineName         string
+	ChaosUID           clientTypes.UID
+	InstanceID         string
+	ChaosNamespace     string
+	ChaosPodName       string
+	Timeout            int
+	Delay              int
+	AppVMMoids         string
+	AppVMNames         string
+	VMTags             string
+	RecoveryConfigMaps string
+	vmwareEnv.VcenterDetails
+}
@@ -5,6 +5,7 @@ import (
 
 	clientTypes "k8s.io/apimachinery/pkg/types"
 
+	vmwareEnv "github.com/chaosnative/litmus-go/pkg/vmware/environment"
 	experimentTypes "github.com/chaosnative/litmus-go/pkg/vmware/vmware-disk-loss/types"
 	"github.com/litmuschaos/litmus-go/pkg/types"
 )
@@ -30,8 +31,13 @@ func GetENV(experimentDetails *experimentTypes.ExperimentDetails) {
 	experimentDetails.Timeout, _ = strconv.Atoi(types.Getenv("STATUS_CHECK_TIMEOUT", "180"))
 	experimentDetails.Sequence = types.Getenv("SEQUENCE", "parallel")
 	experimentDetails.AppVMMoids = types.Getenv("APP_VM_MOIDS", "")
+	experimentDetails.AppVMNames = types.Getenv("APP_VM_NAMES", "")
+	experimentDetails.VMTags = types.Getenv("VM_TAGS", "")
 	experimentDetails.DiskIds = types.Getenv("VIRTUAL_DISK_IDS", "")
-	experimentDetails.VcenterServer = types.Getenv("VCENTERSERVER", "")
-	experimentDetails.VcenterUser = types.Getenv("VCENTERUSER", "")
-	experimentDetails.VcenterPass = types.Getenv("VCENTERPASS", "")
+	experimentDetails.DiskSelectors = types.Getenv("VIRTUAL_DISK_SELECTORS", "")
+	experimentDetails.DiskAffectedPerc, _ = strconv.Atoi(types.Getenv("DISK_AFFECTED_PERC", "100"))
+	experimentDetails.RandomSeed, _ = strconv.ParseInt(types.Getenv("RANDOM_SEED", "0"), 10, 64)
+	experimentDetails.AllowBootDetach, _ = strconv.ParseBool(types.Getenv("ALLOW_BOOT_DISK_DETACH", "false"))
+	experimentDetails.DryRun, _ = strconv.ParseBool(types.Getenv("DRY_RUN", "false"))
+	vmwareEnv.GetVcenterENV(&experimentDetails.VcenterDetails)
 }
@@ -1,6 +1,7 @@
 package types
 
 import (
+	vmwareEnv "github.com/chaosnative/litmus-go/pkg/vmware/environment"
 	clientTypes "k8s.io/apimachinery/pkg/types"
 )
 
@@ -23,10 +24,15 @@ type ExperimentDetails struct {
 	Delay            int
 	Sequence         string
 	AppVMMoids       string
+	AppVMNames       string
+	VMTags           string
 	DiskIds          string
-	VcenterServer    string
-	VcenterUser      string
-	VcenterPass      string
+	DiskSelectors    string
+	DiskAffectedPerc int
+	RandomSeed       int64
+	AllowBootDetach  bool
+	DryRun           bool
 	AuxiliaryAppInfo string
 	TargetContainer  string
+	vmwareEnv.VcenterDetails
 }
@@ -0,0 +1,35 @@
+package environment
+
+import (
+	"strconv"
+
+	clientTypes "k8s.io/apimachinery/pkg/types"
+
+	vmwareEnv "github.com/chaosnative/litmus-go/pkg/vmware/environment"
+	experimentTypes "github.com/chaosnative/litmus-go/pkg/vmware/vmware-host-maintenance/types"
+	"github.com/litmuschaos/litmus-go/pkg/types"
+)
+
+//GetENV fetches all the env variables from the runner pod
+func GetENV(experimentDetails *experimentTypes.ExperimentDetails) {
+	experimentDetails.ExperimentName = types.Getenv("EXPERIMENT_NAME", "vmware-host-maintenance")
+	experimentDetails.ChaosNamespace = types.Getenv("CHAOS_NAMESPACE", "litmus")
+	experimentDetails.EngineName = types.Getenv("CHAOSENGINE", "")
+	experimentDetails.ChaosDuration, _ = strconv.Atoi(types.Getenv("TOTAL_CHAOS_DURATION", "300"))
+	experimentDetails.RampTime, _ = strconv.Atoi(types.Getenv("RAMP_TIME", ""))
+	experimentDetails.ChaosLib = types.Getenv("LIB", "litmus")
+	experimentDetails.AppNS = types.Getenv("APP_NAMESPACE", "")
+	experimentDetails.AppLabel = types.Getenv("APP_LABEL", "")
+	experimentDetails.AppKind = types.Getenv("APP_KIND", "")
+	experimentDetails.ChaosUID = clientTypes.UID(types.Getenv("CHAOS_UID", ""))
+	experimentDetails.InstanceID = types.Getenv("INSTANCE_ID", "")
+	experimentDetails.ChaosPodName = types.Getenv("POD_NAME", "")
+	experimentDetails.AuxiliaryAppInfo = types.Getenv("AUXILIARY_APPINFO", "")
+	experimentDetails.TargetContainer = types.Getenv("TARGET_CONTAINER", "")
+	experimentDetails.Delay, _ = strconv.Atoi(types.Getenv("STATUS_CHECK_DELAY", "2"))
+	experimentDetails.Timeout, _ = strconv.Atoi(types.Getenv("STATUS_CHECK_TIMEOUT", "180"))
+	experimentDetails.HostName = types.Getenv("HOST_NAME", "")
+	experimentDetails.EvacuatePoweredOffVMs, _ = strconv.ParseBool(types.Getenv("EVACUATE_POWERED_OFF_VMS", "false"))
+	experimentDetails.MaintenanceTimeout, _ = strconv.Atoi(types.Getenv("MAINTENANCE_MODE_TIMEOUT", "900"))
+	vmwareEnv.GetVcenterENV(&experimentDetails.VcenterDetails)
+}
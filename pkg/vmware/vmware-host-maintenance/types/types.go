@@ -0,0 +1,31 @@
+package types
+
+import (
+	vmwareEnv "github.com/chaosnative/litmus-go/pkg/vmware/environment"
+	clientTypes "k8s.io/apimachinery/pkg/types"
+)
+
+// ExperimentDetails is for collecting all the experiment-related details
+type ExperimentDetails struct {
+	ExperimentName        string
+	EngineName            string
+	ChaosDuration         int
+	RampTime              int
+	ChaosLib              string
+	AppNS                 string
+	AppLabel              string
+	AppKind               string
+	ChaosUID              clientTypes.UID
+	InstanceID            string
+	ChaosNamespace        string
+	ChaosPodName          string
+	Timeout               int
+	Delay                 int
+	HostName              string
+	HostMoid              string
+	EvacuatePoweredOffVMs bool
+	MaintenanceTimeout    int
+	AuxiliaryAppInfo      string
+	TargetContainer       string
+	vmwareEnv.VcenterDetails
+}
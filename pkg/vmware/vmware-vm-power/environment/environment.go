@@ -0,0 +1,42 @@
+package environment
+
+import (
+	"strconv"
+	"strings"
+
+	clientTypes "k8s.io/apimachinery/pkg/types"
+
+	vmwareEnv "github.com/chaosnative/litmus-go/pkg/vmware/environment"
+	experimentTypes "github.com/chaosnative/litmus-go/pkg/vmware/vmware-vm-power/types"
+	"github.com/litmuschaos/litmus-go/pkg/types"
+)
+
+//GetENV fetches all the env variables from the runner pod
+//the experiment name and the power action are provided by the experiment sharing the power chaos lib
+func GetENV(experimentDetails *experimentTypes.ExperimentDetails, experimentName, powerAction string) {
+	experimentDetails.ExperimentName = types.Getenv("EXPERIMENT_NAME", experimentName)
+	experimentDetails.ChaosNamespace = types.Getenv("CHAOS_NAMESPACE", "litmus")
+	experimentDetails.EngineName = types.Getenv("CHAOSENGINE", "")
+	experimentDetails.ChaosDuration, _ = strconv.Atoi(types.Getenv("TOTAL_CHAOS_DURATION", "30"))
+	experimentDetails.ChaosInterval, _ = strconv.Atoi(types.Getenv("CHAOS_INTERVAL", "30"))
+	experimentDetails.RampTime, _ = strconv.Atoi(types.Getenv("RAMP_TIME", ""))
+	experimentDetails.ChaosLib = types.Getenv("LIB", "litmus")
+	experimentDetails.AppNS = types.Getenv("APP_NAMESPACE", "")
+	experimentDetails.AppLabel = types.Getenv("APP_LABEL", "")
+	experimentDetails.AppKind = types.Getenv("APP_KIND", "")
+	experimentDetails.ChaosUID = clientTypes.UID(types.Getenv("CHAOS_UID", ""))
+	experimentDetails.InstanceID = types.Getenv("INSTANCE_ID", "")
+	experimentDetails.ChaosPodName = types.Getenv("POD_NAME", "")
+	experimentDetails.AuxiliaryAppInfo = types.Getenv("AUXILIARY_APPINFO", "")
+	experimentDetails.TargetContainer = types.Getenv("TARGET_CONTAINER", "")
+	experimentDetails.Delay, _ = strconv.Atoi(types.Getenv("STATUS_CHECK_DELAY", "2"))
+	experimentDetails.Timeout, _ = strconv.Atoi(types.Getenv("STATUS_CHECK_TIMEOUT", "180"))
+	experimentDetails.Sequence = types.Getenv("SEQUENCE", "parallel")
+	experimentDetails.AppVMMoids = types.Getenv("APP_VM_MOIDS", "")
+	experimentDetails.AppVMNames = types.Getenv("APP_VM_NAMES", "")
+	experimentDetails.VMTags = types.Getenv("VM_TAGS", "")
+	experimentDetails.PowerAction = powerAction
+	experimentDetails.GuestAction = strings.ToLower(types.Getenv("GUEST_POWER_ACTION", "reboot"))
+	experimentDetails.WaitForTools, _ = strconv.ParseBool(types.Getenv("WAIT_FOR_VM_TOOLS", "true"))
+	vmwareEnv.GetVcenterENV(&experimentDetails.VcenterDetails)
+}
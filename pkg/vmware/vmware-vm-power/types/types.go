@@ -0,0 +1,35 @@
+package types
+
+import (
+	vmwareEnv "github.com/chaosnative/litmus-go/pkg/vmware/environment"
+	clientTypes "k8s.io/apimachinery/pkg/types"
+)
+
+// ExperimentDetails is for collecting all the experiment-related details
+type ExperimentDetails struct {
+	ExperimentName   string
+	EngineName       string
+	ChaosDuration    int
+	ChaosInterval    int
+	RampTime         int
+	ChaosLib         string
+	AppNS            string
+	AppLabel         string
+	AppKind          string
+	ChaosUID         clientTypes.UID
+	InstanceID       string
+	ChaosNamespace   string
+	ChaosPodName     string
+	Timeout          int
+	Delay            int
+	Sequence         string
+	AppVMMoids       string
+	AppVMNames       string
+	VMTags           string
+	PowerAction      string
+	GuestAction      string
+	WaitForTools     bool
+	AuxiliaryAppInfo string
+	TargetContainer  string
+	vmwareEnv.VcenterDetails
+}